@@ -36,6 +36,9 @@ type BBBuilder struct {
 	// ShellBang means generate #! files instead of symlinks.
 	// ShellBang are more portable and just as efficient.
 	ShellBang bool
+
+	// Cache, if its Dir is set, caches the busybox binary.
+	Cache Cache
 }
 
 // DefaultBinaryDir implements Builder.DefaultBinaryDir.
@@ -53,7 +56,9 @@ func (b BBBuilder) Build(l ulog.Logger, af *initramfs.Files, opts Opts) error {
 	if opts.BuildOpts != nil {
 		noStrip = opts.BuildOpts.NoStrip
 	}
-	if err := bb.BuildBusybox(opts.Env, opts.Packages, noStrip, bbPath); err != nil {
+	if err := b.Cache.build(l, "bb", opts, bbPath, func() error {
+		return bb.BuildBusybox(opts.Env, opts.Packages, noStrip, bbPath)
+	}); err != nil {
 		return err
 	}
 
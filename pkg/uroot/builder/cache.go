@@ -0,0 +1,215 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/u-root/u-root/pkg/ulog"
+)
+
+// Cache is a content-addressed cache of busybox binaries, so that rebuilding
+// unchanged commands does not compile them again.
+//
+// A binary is keyed on the Go version and build environment, the build
+// options, the u-root binary doing the build, and the source of every
+// package compiled into the binary: the module version of packages from the
+// module cache, and the contents of the files of all others.
+type Cache struct {
+	// Dir is the directory binaries are cached in. If it is empty, nothing
+	// is cached.
+	Dir string
+}
+
+// build writes the busybox binary of the commands in opts to bbPath, from
+// the cache if it has one, or else by calling build, whose binary it then
+// caches. kind distinguishes the binaries of different builders.
+//
+// Failing to use the cache is logged, not returned; the build just goes ahead
+// without it.
+func (c Cache) build(l ulog.Logger, kind string, opts Opts, bbPath string, build func() error) error {
+	if c.Dir == "" {
+		return build()
+	}
+	key, err := cacheKey(kind, opts)
+	if err != nil {
+		l.Printf("Not caching the busybox binary: %v", err)
+		return build()
+	}
+	cached := filepath.Join(c.Dir, kind+"-"+key)
+	if err := copyFile(cached, bbPath); err == nil {
+		l.Printf("Using cached busybox binary %s", cached)
+		return nil
+	}
+	if err := build(); err != nil {
+		return err
+	}
+	if err := c.store(bbPath, cached); err != nil {
+		l.Printf("Could not cache the busybox binary: %v", err)
+	}
+	return nil
+}
+
+// store copies the binary at src into the cache as dst. Concurrent builds
+// never see a partially written dst.
+func (c Cache) store(src, dst string) error {
+	if err := os.MkdirAll(c.Dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(c.Dir, ".tmp-")
+	if err != nil {
+		return err
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+	if err := copyFile(src, tmp.Name()); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), dst)
+}
+
+// copyFile copies the executable at src to dst.
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o755)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// listedPackage matches the subset of the JSON output of `go list -json`
+// which determines the compiled package.
+type listedPackage struct {
+	ImportPath string
+	Dir        string
+	Standard   bool
+	Module     *struct {
+		Path    string
+		Version string
+		Replace *struct{}
+	}
+	GoFiles    []string
+	CgoFiles   []string
+	SFiles     []string
+	HFiles     []string
+	CFiles     []string
+	EmbedFiles []string
+}
+
+// cacheArchEnv are the environment variables, besides GOOS and GOARCH, which
+// select the instruction set a binary is compiled for.
+var cacheArchEnv = []string{"GOARM", "GO386", "GOAMD64", "GOMIPS", "GOMIPS64", "GOPPC64", "GO111MODULE", "GOFLAGS"}
+
+// cacheKey returns the key of the busybox binary built by the builder kind
+// with opts, the hash of its cacheInputs.
+func cacheKey(kind string, opts Opts) (string, error) {
+	h := sha256.New()
+	if err := cacheInputs(h, kind, opts); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// cacheInputs writes everything the busybox binary built by the builder kind
+// with opts depends on to w, one input per line.
+func cacheInputs(w io.Writer, kind string, opts Opts) error {
+	fmt.Fprintf(w, "u-root busybox cache 1\n%s\n", kind)
+
+	v, err := opts.Env.Version()
+	if err != nil {
+		return fmt.Errorf("could not get the Go version: %v", err)
+	}
+	fmt.Fprintf(w, "%s\n%s\ntags=%s\n", v, opts.Env, strings.Join(opts.Env.BuildTags, ","))
+	for _, e := range cacheArchEnv {
+		fmt.Fprintf(w, "%s=%s\n", e, os.Getenv(e))
+	}
+	if opts.BuildOpts != nil {
+		fmt.Fprintf(w, "%+v\n", *opts.BuildOpts)
+	}
+
+	// The u-root binary rewrites the commands into one busybox.
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	if err := hashFile(w, exe); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "packages %q\n", opts.Packages)
+	args := []string{"list", "-deps", "-json"}
+	if len(opts.Env.BuildTags) > 0 {
+		args = append(args, "-tags", strings.Join(opts.Env.BuildTags, ","))
+	}
+	cmd := opts.Env.GoCmd(append(args, opts.Packages...)...)
+	out, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("could not list the dependencies of %v: %v", opts.Packages, err)
+	}
+	d := json.NewDecoder(bytes.NewReader(out))
+	for d.More() {
+		var p listedPackage
+		if err := d.Decode(&p); err != nil {
+			return fmt.Errorf("could not parse go list output: %v", err)
+		}
+		if p.Standard {
+			// Covered by the Go version.
+			continue
+		}
+		if m := p.Module; m != nil && m.Version != "" && m.Replace == nil && !vendored(p.Dir) {
+			// Modules in the module cache do not change.
+			fmt.Fprintf(w, "%s %s@%s\n", p.ImportPath, m.Path, m.Version)
+			continue
+		}
+		fmt.Fprintf(w, "%s\n", p.ImportPath)
+		for _, files := range [][]string{p.GoFiles, p.CgoFiles, p.SFiles, p.HFiles, p.CFiles, p.EmbedFiles} {
+			for _, f := range files {
+				fmt.Fprintf(w, "%s ", f)
+				if err := hashFile(w, filepath.Join(p.Dir, f)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// vendored reports whether dir is in a vendor directory, where the files of a
+// module version may have been edited.
+func vendored(dir string) bool {
+	return strings.Contains(filepath.ToSlash(dir), "/vendor/")
+}
+
+// hashFile writes the SHA-256 of the contents of file to w.
+func hashFile(w io.Writer, file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fh := sha256.New()
+	if _, err := io.Copy(fh, f); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "%x\n", fh.Sum(nil))
+	return nil
+}
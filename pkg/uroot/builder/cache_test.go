@@ -0,0 +1,190 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	gbbgolang "github.com/u-root/gobusybox/src/pkg/golang"
+	"github.com/u-root/u-root/pkg/golang"
+	"github.com/u-root/u-root/pkg/ulog/ulogtest"
+)
+
+// chdirModule changes into a temporary module example.com/hello for the rest
+// of the test.
+func chdirModule(t *testing.T) {
+	dir := t.TempDir()
+	for name, contents := range map[string]string{
+		"go.mod":     "module example.com/hello\n\ngo 1.17\n",
+		"hello.go":   "package main\n\nfunc main() { println(\"hello\") }\n",
+		"arm/arm.go": "package main\n\nfunc main() {}\n",
+		"notes.txt":  "not part of the build\n",
+		"tagged.go":  "//go:build extra\n\npackage main\n\nfunc init() {}\n",
+	} {
+		if err := os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCacheKey(t *testing.T) {
+	chdirModule(t)
+
+	opts := func(change func(*Opts)) Opts {
+		o := Opts{
+			Env:       golang.Default(),
+			Packages:  []string{"example.com/hello"},
+			BuildOpts: &gbbgolang.BuildOpts{},
+		}
+		if change != nil {
+			change(&o)
+		}
+		return o
+	}
+	want, err := cacheKey("gbb", opts(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := cacheKey("gbb", opts(nil)); err != nil || got != want {
+		t.Errorf("cacheKey() = %q, %v the second time, want %q", got, err, want)
+	}
+	if err := os.WriteFile("notes.txt", []byte("edited\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := cacheKey("gbb", opts(nil)); err != nil || got != want {
+		t.Errorf("cacheKey() = %q, %v after editing a file outside the build, want %q", got, err, want)
+	}
+
+	keys := map[string]string{want: "unchanged"}
+	for _, tt := range []struct {
+		name   string
+		kind   string
+		change func(*Opts)
+		edit   string
+	}{
+		{name: "builder", kind: "bb"},
+		{name: "GOARCH", change: func(o *Opts) { o.Env.GOARCH = "arm64" }},
+		{name: "build tags", change: func(o *Opts) { o.Env.BuildTags = []string{"extra"} }},
+		{name: "build options", change: func(o *Opts) { o.BuildOpts.NoStrip = true }},
+		{name: "packages", change: func(o *Opts) { o.Packages = append(o.Packages, "example.com/hello/arm") }},
+		{name: "source", edit: "package main\n\nfunc main() { println(\"hello, world\") }\n"},
+	} {
+		if tt.kind == "" {
+			tt.kind = "gbb"
+		}
+		if tt.edit != "" {
+			if err := os.WriteFile("hello.go", []byte(tt.edit), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		got, err := cacheKey(tt.kind, opts(tt.change))
+		if err != nil {
+			t.Errorf("cacheKey() with changed %s: %v", tt.name, err)
+			continue
+		}
+		if other, ok := keys[got]; ok {
+			t.Errorf("cacheKey() with changed %s = %q, the same as %s", tt.name, got, other)
+		}
+		keys[got] = tt.name
+	}
+
+	if _, err := cacheKey("gbb", opts(func(o *Opts) { o.Packages = []string{"example.com/missing"} })); err == nil {
+		t.Errorf("cacheKey() of a missing package = nil, want error")
+	}
+}
+
+func TestCacheInputs(t *testing.T) {
+	chdirModule(t)
+
+	opts := Opts{
+		Env:       golang.Default(),
+		Packages:  []string{"example.com/hello"},
+		BuildOpts: &gbbgolang.BuildOpts{NoStrip: true},
+	}
+	opts.Env.GOARCH = "arm64"
+	opts.Env.BuildTags = []string{"extra"}
+	var b bytes.Buffer
+	if err := cacheInputs(&b, "gbb", opts); err != nil {
+		t.Fatal(err)
+	}
+	version, err := opts.Env.Version()
+	if err != nil {
+		t.Fatal(err)
+	}
+	inputs := b.String()
+	for _, want := range []string{
+		"\ngbb\n",
+		"\n" + version + "\n",
+		"GOARCH=arm64 ",
+		"\ntags=extra\n",
+		"\n{NoStrip:true ",
+		"\npackages [\"example.com/hello\"]\n",
+		"\nexample.com/hello\n",
+		"\nhello.go ",
+		"\ntagged.go ",
+	} {
+		if !strings.Contains(inputs, want) {
+			t.Errorf("cacheInputs() = %q, want it to contain %q", inputs, want)
+		}
+	}
+	if strings.Contains(inputs, "notes.txt") {
+		t.Errorf("cacheInputs() = %q, want no file outside the build", inputs)
+	}
+}
+
+func TestCacheBuild(t *testing.T) {
+	c := Cache{Dir: filepath.Join(t.TempDir(), "cache")}
+	opts := Opts{
+		Env:       golang.Default(),
+		Packages:  []string{"../test/foo"},
+		BuildOpts: &gbbgolang.BuildOpts{},
+	}
+	var builds int
+	for i := 0; i < 2; i++ {
+		bbPath := filepath.Join(t.TempDir(), "bb")
+		if err := c.build(ulogtest.Logger{TB: t}, "gbb", opts, bbPath, func() error {
+			builds++
+			return os.WriteFile(bbPath, []byte("busybox"), 0o755)
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if got, err := os.ReadFile(bbPath); err != nil || string(got) != "busybox" {
+			t.Errorf("build %d wrote %q, %v, want %q", i, got, err, "busybox")
+		}
+	}
+	if builds != 1 {
+		t.Errorf("built %d times, want 1 with the cache", builds)
+	}
+
+	// Without a Dir, nothing is cached.
+	builds = 0
+	for i := 0; i < 2; i++ {
+		bbPath := filepath.Join(t.TempDir(), "bb")
+		if err := (Cache{}).build(ulogtest.Logger{TB: t}, "gbb", opts, bbPath, func() error {
+			builds++
+			return os.WriteFile(bbPath, []byte("busybox"), 0o755)
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if builds != 2 {
+		t.Errorf("built %d times, want 2 without the cache", builds)
+	}
+}
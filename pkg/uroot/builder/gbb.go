@@ -34,6 +34,9 @@ type GBBBuilder struct {
 	// ShellBang means generate #! files instead of symlinks.
 	// ShellBang are more portable and just as efficient.
 	ShellBang bool
+
+	// Cache, if its Dir is set, caches the busybox binary.
+	Cache Cache
 }
 
 // DefaultBinaryDir implements Builder.DefaultBinaryDir.
@@ -70,7 +73,9 @@ func (b GBBBuilder) Build(l ulog.Logger, af *initramfs.Files, opts Opts) error {
 		GoBuildOpts:  opts.BuildOpts,
 	}
 
-	if err := bb.BuildBusybox(l, bopts); err != nil {
+	if err := b.Cache.build(l, "gbb", opts, bbPath, func() error {
+		return bb.BuildBusybox(l, bopts)
+	}); err != nil {
 		// Print the actual error. This may contain a suggestion for
 		// what to do, actually.
 		l.Printf("Gobusybox error: %v", err)
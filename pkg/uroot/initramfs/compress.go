@@ -0,0 +1,99 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package initramfs
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/klauspost/pgzip"
+	"github.com/pierrec/lz4/v4"
+	"github.com/ulikunitz/xz"
+)
+
+// Compressor is a compression format the Linux kernel can unpack an
+// initramfs from.
+type Compressor struct {
+	// Ext is the extension of compressed files, e.g. ".gz".
+	Ext string
+
+	// MaxLevel is the highest compression level. Levels range from 1 to
+	// MaxLevel; 0 is the default level of the format.
+	MaxLevel int
+
+	// NewWriter returns a writer compressing to w at level.
+	NewWriter func(w io.Writer, level int) (io.WriteCloser, error)
+}
+
+// Compressors are the supported initramfs compressions.
+//
+// gzip, zstd and lz4 compress on all CPUs; xz has no parallel encoder.
+var Compressors = map[string]Compressor{
+	"gzip": {".gz", 9, newGzipWriter},
+	"lz4":  {".lz4", 9, newLZ4Writer},
+	"xz":   {".xz", 9, newXZWriter},
+	"zstd": {".zst", 22, newZstdWriter},
+}
+
+// GetCompressor finds a supported compression by name, and checks that level
+// is valid for it.
+func GetCompressor(name string, level int) (Compressor, error) {
+	c, ok := Compressors[name]
+	if !ok {
+		var names []string
+		for n := range Compressors {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return Compressor{}, fmt.Errorf("couldn't find compression %q, want one of %v", name, names)
+	}
+	if level < 0 || level > c.MaxLevel {
+		return Compressor{}, fmt.Errorf("%s compression level %d is out of range 1-%d, or 0 for the default level", name, level, c.MaxLevel)
+	}
+	return c, nil
+}
+
+func newGzipWriter(w io.Writer, level int) (io.WriteCloser, error) {
+	if level == 0 {
+		level = pgzip.DefaultCompression
+	}
+	return pgzip.NewWriterLevel(w, level)
+}
+
+func newZstdWriter(w io.Writer, level int) (io.WriteCloser, error) {
+	opts := []zstd.EOption{zstd.WithEncoderConcurrency(runtime.NumCPU())}
+	if level != 0 {
+		opts = append(opts, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(level)))
+	}
+	return zstd.NewWriter(w, opts...)
+}
+
+// xzDictCaps are the dictionary sizes of the xz presets 1 to 9.
+var xzDictCaps = []int{1 << 20, 2 << 20, 4 << 20, 4 << 20, 8 << 20, 8 << 20, 16 << 20, 32 << 20, 64 << 20}
+
+func newXZWriter(w io.Writer, level int) (io.WriteCloser, error) {
+	// The kernel's xz decoder only verifies CRC32 checksums.
+	c := xz.WriterConfig{CheckSum: xz.CRC32}
+	if level != 0 {
+		c.DictCap = xzDictCaps[level-1]
+	}
+	return c.NewWriter(w)
+}
+
+func newLZ4Writer(w io.Writer, level int) (io.WriteCloser, error) {
+	// The kernel only unpacks the legacy lz4 format.
+	opts := []lz4.Option{lz4.LegacyOption(true), lz4.ConcurrencyOption(-1)}
+	if level != 0 {
+		opts = append(opts, lz4.CompressionLevelOption(lz4.CompressionLevel(1<<(8+level))))
+	}
+	zw := lz4.NewWriter(w)
+	if err := zw.Apply(opts...); err != nil {
+		return nil, err
+	}
+	return zw, nil
+}
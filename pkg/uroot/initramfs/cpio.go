@@ -16,6 +16,14 @@ import (
 // CPIOArchiver is an implementation of Archiver for the cpio format.
 type CPIOArchiver struct {
 	cpio.RecordFormat
+
+	// Compression, if not empty, names one of Compressors to compress the
+	// archive with.
+	Compression string
+
+	// CompressionLevel is the level to compress at, or 0 for the default
+	// level of the Compression.
+	CompressionLevel int
 }
 
 // OpenWriter opens `path` as the correct file type and returns an
@@ -24,11 +32,26 @@ func (ca CPIOArchiver) OpenWriter(l ulog.Logger, path string) (Writer, error) {
 	if len(path) == 0 {
 		return nil, fmt.Errorf("path is required")
 	}
+	var c Compressor
+	if ca.Compression != "" {
+		var err error
+		if c, err = GetCompressor(ca.Compression, ca.CompressionLevel); err != nil {
+			return nil, err
+		}
+	}
 	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
 	if err != nil {
 		return nil, err
 	}
-	return osWriter{ca.RecordFormat.Writer(f), f}, nil
+	if c.NewWriter == nil {
+		return osWriter{ca.RecordFormat.Writer(f), f, nil}, nil
+	}
+	zw, err := c.NewWriter(f, ca.CompressionLevel)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return osWriter{ca.RecordFormat.Writer(zw), f, zw}, nil
 }
 
 // osWriter implements Writer.
@@ -36,11 +59,19 @@ type osWriter struct {
 	cpio.RecordWriter
 
 	f *os.File
+
+	// zw compresses the records written to f, if not nil.
+	zw io.WriteCloser
 }
 
 // Finish implements Writer.Finish.
 func (o osWriter) Finish() error {
 	err := cpio.WriteTrailer(o)
+	if o.zw != nil {
+		if cerr := o.zw.Close(); err == nil {
+			err = cerr
+		}
+	}
 	o.f.Close()
 	return err
 }
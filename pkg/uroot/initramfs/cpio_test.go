@@ -0,0 +1,126 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package initramfs
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4/v4"
+	"github.com/u-root/u-root/pkg/cpio"
+	"github.com/u-root/u-root/pkg/ulog/ulogtest"
+	"github.com/ulikunitz/xz"
+)
+
+func TestCPIOArchiverCompression(t *testing.T) {
+	motd := cpio.StaticFile("etc/motd", strings.Repeat("u-root initramfs ", 1<<16), 0o644)
+	for _, tt := range []struct {
+		compression string
+		level       int
+		newReader   func(r io.Reader) (io.Reader, error)
+	}{
+		{
+			compression: "gzip",
+			newReader: func(r io.Reader) (io.Reader, error) {
+				return gzip.NewReader(r)
+			},
+		},
+		{
+			compression: "gzip",
+			level:       9,
+			newReader: func(r io.Reader) (io.Reader, error) {
+				return gzip.NewReader(r)
+			},
+		},
+		{
+			compression: "zstd",
+			level:       19,
+			newReader: func(r io.Reader) (io.Reader, error) {
+				return zstd.NewReader(r)
+			},
+		},
+		{
+			compression: "xz",
+			level:       1,
+			newReader: func(r io.Reader) (io.Reader, error) {
+				return xz.NewReader(r)
+			},
+		},
+		{
+			compression: "lz4",
+			newReader: func(r io.Reader) (io.Reader, error) {
+				return lz4.NewReader(r), nil
+			},
+		},
+	} {
+		t.Run(tt.compression, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "initramfs.cpio")
+			ca := CPIOArchiver{RecordFormat: cpio.Newc, Compression: tt.compression, CompressionLevel: tt.level}
+			w, err := ca.OpenWriter(ulogtest.Logger{TB: t}, path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := w.WriteRecord(motd); err != nil {
+				t.Fatal(err)
+			}
+			if err := w.Finish(); err != nil {
+				t.Fatal(err)
+			}
+
+			f, err := os.Open(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			if fi, err := f.Stat(); err != nil || fi.Size() >= int64(motd.FileSize) {
+				t.Errorf("compressed archive has size %d, want less than %d", fi.Size(), motd.FileSize)
+			}
+			zr, err := tt.newReader(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			archive, err := io.ReadAll(zr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			records, err := cpio.ReadAllRecords(cpio.Newc.Reader(bytes.NewReader(archive)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := []cpio.Record{motd}; !cpio.AllEqual(records, want) {
+				t.Errorf("archive has records %v, want %v", records, want)
+			}
+		})
+	}
+}
+
+func TestGetCompressor(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		level int
+		err   string
+	}{
+		{"gzip", 0, ""},
+		{"zstd", 22, ""},
+		{"xz", 10, "xz compression level 10 is out of range 1-9, or 0 for the default level"},
+		{"lz4", -1, "lz4 compression level -1 is out of range 1-9, or 0 for the default level"},
+		{"bzip2", 0, `couldn't find compression "bzip2", want one of [gzip lz4 xz zstd]`},
+	} {
+		_, err := GetCompressor(tt.name, tt.level)
+		var got string
+		if err != nil {
+			got = err.Error()
+		}
+		if got != tt.err {
+			t.Errorf("GetCompressor(%q, %d) = %v, want %q", tt.name, tt.level, err, tt.err)
+		}
+	}
+}
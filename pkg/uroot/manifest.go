@@ -0,0 +1,253 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uroot
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/u-root/u-root/pkg/cpio"
+	"github.com/u-root/u-root/pkg/uio"
+	"github.com/u-root/u-root/pkg/uroot/initramfs"
+	"gopkg.in/yaml.v2"
+)
+
+// Manifest declares files to add to an initramfs, with the metadata the host
+// files added by ExtraFiles cannot express.
+//
+// A manifest is read from YAML, or from JSON if its name ends in .json:
+//
+//	files:
+//	  - path: etc/hostname
+//	    contents: "uroot\n"
+//	  - path: bin/tool
+//	    source: /usr/local/bin/tool
+//	    mode: "0750"
+//	    gid: 10
+//	  - path: dev/console
+//	    type: char
+//	    major: 5
+//	    minor: 1
+//	    mode: "0600"
+//	  - path: var/log
+//	    type: dir
+//	  - path: bin/vi
+//	    type: symlink
+//	    target: /bbin/elvish
+type Manifest struct {
+	Files []ManifestFile `json:"files" yaml:"files"`
+}
+
+// ManifestFile is one file of a Manifest.
+type ManifestFile struct {
+	// Path is where the file is in the archive.
+	Path string `json:"path" yaml:"path"`
+
+	// Type is one of "file" (the default), "dir", "symlink", "char",
+	// "block" or "fifo".
+	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+
+	// Source is the host file whose contents a file has. Its permissions
+	// are the default Mode.
+	Source string `json:"source,omitempty" yaml:"source,omitempty"`
+
+	// Contents are the contents of a file without a Source.
+	Contents string `json:"contents,omitempty" yaml:"contents,omitempty"`
+
+	// Target is what a symlink points to.
+	Target string `json:"target,omitempty" yaml:"target,omitempty"`
+
+	// Major and Minor are the device numbers of a char or block device.
+	Major uint64 `json:"major,omitempty" yaml:"major,omitempty"`
+	Minor uint64 `json:"minor,omitempty" yaml:"minor,omitempty"`
+
+	// Mode holds the permission bits, e.g. "0755". It defaults to 0755 for
+	// directories, 0777 for symlinks, and 0644 otherwise.
+	Mode FileMode `json:"mode,omitempty" yaml:"mode,omitempty"`
+
+	// UID and GID own the file.
+	UID uint64 `json:"uid,omitempty" yaml:"uid,omitempty"`
+	GID uint64 `json:"gid,omitempty" yaml:"gid,omitempty"`
+}
+
+// FileMode holds permission bits. It is written as an octal string like
+// "0755", or as a number, which in YAML may be an octal literal like 0755.
+type FileMode struct {
+	Perm uint64
+	Set  bool
+}
+
+// setOctal sets the permission bits written as the octal string s.
+func (m *FileMode) setOctal(s string) error {
+	perm, err := strconv.ParseUint(s, 8, 64)
+	if err != nil {
+		return fmt.Errorf("mode %q is not octal", s)
+	}
+	return m.setPerm(perm)
+}
+
+func (m *FileMode) setPerm(perm uint64) error {
+	if perm&^0o7777 != 0 {
+		return fmt.Errorf("mode %#o has more than permission bits", perm)
+	}
+	m.Perm, m.Set = perm, true
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (m *FileMode) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		return m.setOctal(s)
+	}
+	var perm uint64
+	if err := json.Unmarshal(b, &perm); err != nil {
+		return fmt.Errorf("mode %s is neither an octal string nor a number", b)
+	}
+	return m.setPerm(perm)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (m *FileMode) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var perm uint64
+	if err := unmarshal(&perm); err == nil {
+		return m.setPerm(perm)
+	}
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+	return m.setOctal(s)
+}
+
+// ReadManifest reads the Manifest in file.
+func ReadManifest(file string) (*Manifest, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	if filepath.Ext(file) == ".json" {
+		d := json.NewDecoder(bytes.NewReader(b))
+		d.DisallowUnknownFields()
+		err = d.Decode(&m)
+	} else {
+		err = yaml.UnmarshalStrict(b, &m)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not parse manifest %s: %v", file, err)
+	}
+	return &m, nil
+}
+
+// Records returns the cpio records of the files in m.
+func (m *Manifest) Records() ([]cpio.Record, error) {
+	var records []cpio.Record
+	for _, f := range m.Files {
+		r, err := f.record()
+		if err != nil {
+			return nil, fmt.Errorf("manifest file %q: %v", f.Path, err)
+		}
+		records = append(records, r)
+	}
+	return records, nil
+}
+
+func (f ManifestFile) record() (cpio.Record, error) {
+	if f.Path == "" {
+		return cpio.Record{}, fmt.Errorf("path is required")
+	}
+	name := cpio.Normalize(f.Path)
+	typ := f.Type
+	if typ == "" {
+		typ = "file"
+	}
+	if typ != "file" && (f.Source != "" || f.Contents != "") {
+		return cpio.Record{}, fmt.Errorf("only files have a source or contents, not a %s", typ)
+	}
+	if typ != "symlink" && f.Target != "" {
+		return cpio.Record{}, fmt.Errorf("only symlinks have a target, not a %s", typ)
+	}
+	if typ != "char" && typ != "block" && (f.Major != 0 || f.Minor != 0) {
+		return cpio.Record{}, fmt.Errorf("only devices have major and minor numbers, not a %s", typ)
+	}
+
+	perm := f.Mode.Perm
+	var r cpio.Record
+	switch typ {
+	case "file":
+		if !f.Mode.Set {
+			perm = 0o644
+		}
+		switch {
+		case f.Source != "" && f.Contents != "":
+			return cpio.Record{}, fmt.Errorf("a file has either a source or contents")
+		case f.Source != "":
+			fi, err := os.Stat(f.Source)
+			if err != nil {
+				return cpio.Record{}, err
+			}
+			if !fi.Mode().IsRegular() {
+				return cpio.Record{}, fmt.Errorf("source %s is not a regular file", f.Source)
+			}
+			if !f.Mode.Set {
+				perm = uint64(fi.Mode().Perm())
+			}
+			r = cpio.Record{
+				ReaderAt: uio.NewLazyFile(f.Source),
+				Info:     cpio.Info{Name: name, Mode: cpio.S_IFREG | perm, FileSize: uint64(fi.Size())},
+			}
+		default:
+			r = cpio.StaticFile(name, f.Contents, perm)
+		}
+	case "dir":
+		if !f.Mode.Set {
+			perm = 0o755
+		}
+		r = cpio.Directory(name, perm)
+	case "symlink":
+		if f.Target == "" {
+			return cpio.Record{}, fmt.Errorf("a symlink needs a target")
+		}
+		r = cpio.Symlink(name, f.Target)
+		if f.Mode.Set {
+			r.Mode = cpio.S_IFLNK | perm
+		}
+	case "char", "block", "fifo":
+		if !f.Mode.Set {
+			perm = 0o644
+		}
+		ifmt := map[string]uint64{"char": cpio.S_IFCHR, "block": cpio.S_IFBLK, "fifo": cpio.S_IFIFO}[typ]
+		r = cpio.Record{Info: cpio.Info{Name: name, Mode: ifmt | perm, Rmajor: f.Major, Rminor: f.Minor}}
+	default:
+		return cpio.Record{}, fmt.Errorf("unknown type %q, want file, dir, symlink, char, block or fifo", typ)
+	}
+	r.UID, r.GID = f.UID, f.GID
+	return r, nil
+}
+
+// ParseManifests adds the files of the manifests to the archive.
+func ParseManifests(archive *initramfs.Files, manifests []string) error {
+	for _, file := range manifests {
+		m, err := ReadManifest(file)
+		if err != nil {
+			return err
+		}
+		records, err := m.Records()
+		if err != nil {
+			return fmt.Errorf("%s: %v", file, err)
+		}
+		for _, r := range records {
+			if err := archive.AddRecord(r); err != nil {
+				return fmt.Errorf("%s: %v", file, err)
+			}
+		}
+	}
+	return nil
+}
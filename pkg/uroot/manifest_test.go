@@ -0,0 +1,117 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uroot
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/u-root/u-root/pkg/cpio"
+	"github.com/u-root/u-root/pkg/uroot/initramfs"
+)
+
+func TestManifest(t *testing.T) {
+	dir := t.TempDir()
+	tool := filepath.Join(dir, "tool")
+	if err := os.WriteFile(tool, []byte("#!/bin/sh\n"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	yamlManifest := filepath.Join(dir, "files.yaml")
+	if err := os.WriteFile(yamlManifest, []byte(`files:
+  - path: /etc/hostname
+    contents: "uroot\n"
+  - path: bin/tool
+    source: `+tool+`
+  - path: sbin/tool
+    source: `+tool+`
+    mode: "0750"
+    gid: 10
+  - path: dev/console
+    type: char
+    major: 5
+    minor: 1
+    mode: 0600
+  - path: dev/sda
+    type: block
+    major: 8
+  - path: run/initctl
+    type: fifo
+  - path: home/user
+    type: dir
+    mode: "0700"
+    uid: 1000
+    gid: 1000
+  - path: bin/vi
+    type: symlink
+    target: /bbin/elvish
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	jsonManifest := filepath.Join(dir, "files.json")
+	if err := os.WriteFile(jsonManifest, []byte(`{"files": [{"path": "etc/motd", "contents": "hi", "mode": "0444"}]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	af := initramfs.NewFiles()
+	if err := ParseManifests(af, []string{yamlManifest, jsonManifest}); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []cpio.Info{
+		{Name: "etc/hostname", Mode: cpio.S_IFREG | 0o644, FileSize: 6},
+		{Name: "bin/tool", Mode: cpio.S_IFREG | 0o700, FileSize: 10},
+		{Name: "sbin/tool", Mode: cpio.S_IFREG | 0o750, GID: 10, FileSize: 10},
+		{Name: "dev/console", Mode: cpio.S_IFCHR | 0o600, Rmajor: 5, Rminor: 1},
+		{Name: "dev/sda", Mode: cpio.S_IFBLK | 0o644, Rmajor: 8},
+		{Name: "run/initctl", Mode: cpio.S_IFIFO | 0o644},
+		{Name: "home/user", Mode: cpio.S_IFDIR | 0o700, UID: 1000, GID: 1000},
+		{Name: "bin/vi", Mode: cpio.S_IFLNK | 0o777, FileSize: uint64(len("/bbin/elvish"))},
+		{Name: "etc/motd", Mode: cpio.S_IFREG | 0o444, FileSize: 2},
+	} {
+		r, ok := af.Records[want.Name]
+		if !ok {
+			t.Errorf("manifest did not add %q", want.Name)
+			continue
+		}
+		if r.Info != want {
+			t.Errorf("manifest added %v, want %v", r.Info, want)
+		}
+	}
+	r := af.Records["sbin/tool"]
+	if b := make([]byte, 10); r.ReaderAt == nil {
+		t.Errorf("sbin/tool has no contents")
+	} else if _, err := r.ReadAt(b, 0); err != nil || string(b) != "#!/bin/sh\n" {
+		t.Errorf("sbin/tool has contents %q, %v, want those of the source", b, err)
+	}
+}
+
+func TestManifestErrors(t *testing.T) {
+	for _, tt := range []struct {
+		manifest string
+		err      string
+	}{
+		{"files:\n  - contents: x\n", "path is required"},
+		{"files:\n  - path: a\n    type: sock\n", `unknown type "sock"`},
+		{"files:\n  - path: a\n    type: dir\n    contents: x\n", "only files have a source or contents"},
+		{"files:\n  - path: a\n    target: b\n", "only symlinks have a target, not a file"},
+		{"files:\n  - path: a\n    type: fifo\n    major: 1\n", "only devices have major and minor numbers"},
+		{"files:\n  - path: a\n    type: symlink\n", "a symlink needs a target"},
+		{"files:\n  - path: a\n    source: /nonexistent\n", "no such file"},
+		{"files:\n  - path: a\n    mode: \"0999\"\n", "is not octal"},
+		{"files:\n  - path: a\n    mode: \"17777\"\n", "more than permission bits"},
+		{"files:\n  - path: a\n    owner: root\n", "owner"},
+		{"files:\n  - path: a\n  - path: a\n    mode: \"0600\"\n", "already exists"},
+	} {
+		file := filepath.Join(t.TempDir(), "files.yaml")
+		if err := os.WriteFile(file, []byte(tt.manifest), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		err := ParseManifests(initramfs.NewFiles(), []string{file})
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("ParseManifests(%q) = %v, want error containing %q", tt.manifest, err, tt.err)
+		}
+	}
+}
@@ -169,6 +169,10 @@ type Opts struct {
 	// will misbehave.
 	SkipLDD bool
 
+	// Manifests are YAML or JSON files declaring more files to add to the
+	// archive, with their type, ownership and permissions; see Manifest.
+	Manifests []string
+
 	// OutputFile is the archive output file.
 	OutputFile initramfs.Writer
 
@@ -271,6 +275,9 @@ func CreateInitramfs(logger ulog.Logger, opts Opts) error {
 	if err := ParseExtraFiles(logger, archive.Files, opts.ExtraFiles, !opts.SkipLDD); err != nil {
 		return err
 	}
+	if err := ParseManifests(archive.Files, opts.Manifests); err != nil {
+		return err
+	}
 
 	if err := opts.addSymlinkTo(logger, archive, opts.UinitCmd, "bin/uinit"); err != nil {
 		return fmt.Errorf("%v: specify -uinitcmd=\"\" to ignore this error and build without a uinit", err)
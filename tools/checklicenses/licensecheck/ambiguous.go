@@ -0,0 +1,17 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+// matchingLicenses returns the ids of all Licenses which any of texts match,
+// in configuration order.
+func (c *Config) matchingLicenses(texts [][]byte) []string {
+	var ids []string
+	for i, re := range c.licensesRegexps {
+		if matchTexts(re, texts) {
+			ids = append(ids, c.Licenses[i].id("Licenses", i))
+		}
+	}
+	return ids
+}
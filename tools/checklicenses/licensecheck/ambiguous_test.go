@@ -0,0 +1,33 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import "testing"
+
+func TestStrictSingleLicense(t *testing.T) {
+	c := &Config{Licenses: []License{
+		{Name: "BSD", Lines: []string{"^// Copyright .* BSD"}},
+		{Lines: []string{"^// Copyright"}},
+		{Name: "MIT", Lines: []string{"^// MIT"}},
+	}}
+	if err := c.CompileRegexps(); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		strict bool
+		in     string
+		want   violation
+	}{
+		{false, "// Copyright 2026 X, BSD\npackage a\n", violation{file: "a.go"}},
+		{true, "// Copyright 2026 X, BSD\npackage a\n", violation{file: "a.go", bucket: bucketAmbiguous, detail: "matches each of BSD, Licenses[1]"}},
+		{true, "// Copyright 2026 X\npackage a\n", violation{file: "a.go"}},
+		{true, "// MIT\npackage a\n", violation{file: "a.go"}},
+	} {
+		cd := candidate{file: "a.go", config: c, opts: Options{StrictSingleLicense: tt.strict}}
+		if got := cd.classify("a.go", []byte(tt.in)); got != tt.want {
+			t.Errorf("strict %t: classify(%q) = %+v, want %+v", tt.strict, tt.in, got, tt.want)
+		}
+	}
+}
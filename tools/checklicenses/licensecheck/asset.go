@@ -0,0 +1,95 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+)
+
+// AssetDir gives the files in directories of assets, like the templates and
+// static files embedded with //go:embed, their own license policy.
+type AssetDir struct {
+	// DirGlob is a glob matching directories relative to GoPkg, e.g.
+	// "pkg/*/static". It applies to their subdirectories too.
+	DirGlob string
+	// Licenses replace the Licenses of the configuration for the assets.
+	// If empty, the assets are checked against the configuration's.
+	Licenses []License `json:",omitempty"`
+	// CommentStyle is the comment syntax the assets carry their header
+	// in, whatever their extension: "c" for "//" and "/* */", "hash" for
+	// "#", "markup" for "<!-- -->", or "template" for Go template
+	// comments, "{{/* */}}". If empty, it follows from the extension.
+	CommentStyle string `json:",omitempty"`
+	config       *Config
+}
+
+// templateStyle is the comment syntax of Go templates.
+var templateStyle = commentStyle{blockStart: "{{/*", blockEnd: "*/}}"}
+
+// namedStyles maps the values of AssetDir.CommentStyle to their syntax.
+var namedStyles = map[string]commentStyle{
+	"c":        cStyle,
+	"hash":     hashStyle,
+	"markup":   markupStyle,
+	"template": templateStyle,
+}
+
+// compileAssetDirs derives the configuration of each of AssetDirs from the
+// compiled configuration.
+func (c *Config) compileAssetDirs() error {
+	for i := range c.AssetDirs {
+		a := &c.AssetDirs[i]
+		if _, err := path.Match(a.DirGlob, ""); err != nil {
+			return fmt.Errorf("AssetDirs[%d]: invalid glob %q: %v", i, a.DirGlob, err)
+		}
+		ac := *c
+		ac.AssetDirs = nil
+		ac.profile = nil
+		if len(a.Licenses) > 0 {
+			ac.Licenses = a.Licenses
+			ac.licensesRegexps = make([]*regexp.Regexp, 0, len(a.Licenses))
+			for j := range a.Licenses {
+				re, err := a.Licenses[j].compile(fmt.Sprintf("AssetDirs[%d].Licenses", i), j, c.IgnoreYears)
+				if err != nil {
+					return err
+				}
+				ac.licensesRegexps = append(ac.licensesRegexps, re)
+			}
+		}
+		if a.CommentStyle != "" {
+			s, ok := namedStyles[a.CommentStyle]
+			if !ok {
+				return fmt.Errorf("AssetDirs[%d]: unknown CommentStyle %q, want c, hash, markup or template", i, a.CommentStyle)
+			}
+			ac.style = &s
+		}
+		a.config = &ac
+	}
+	return nil
+}
+
+// forAsset returns the configuration of the first of AssetDirs holding file,
+// a path relative to GoPkg, or c if there is none.
+func (c *Config) forAsset(file string) *Config {
+	for _, a := range c.AssetDirs {
+		for dir := path.Dir(file); dir != "." && dir != "/"; dir = path.Dir(dir) {
+			if ok, _ := path.Match(a.DirGlob, dir); ok {
+				return a.config
+			}
+		}
+	}
+	return c
+}
+
+// styleFor is like the function styleFor, but honors the CommentStyle of an
+// AssetDir.
+func (c *Config) styleFor(file string, contents []byte) (commentStyle, bool) {
+	if c.style != nil {
+		return *c.style, true
+	}
+	return styleFor(file, contents)
+}
@@ -0,0 +1,52 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestAssetDirs(t *testing.T) {
+	c, err := loadConfig("testdata/assets/config.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := []string{
+		"testdata/assets/pkg/web/index.html",
+		"testdata/assets/pkg/web/templates/mail/hello.txt",
+		"testdata/assets/pkg/web/templates/page.html",
+		"testdata/assets/pkg/web/web.go",
+	}
+	var got []FileResult
+	if err := c.Scan(context.Background(), sliceLister(files), Options{}, func(r FileResult) {
+		got = append(got, r)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	want := []FileResult{
+		{File: files[0], Bucket: string(bucketMissing)},
+		{File: files[1]},
+		{File: files[2]},
+		{File: files[3]},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Scan() results = %+v, want %+v", got, want)
+	}
+}
+
+func TestAssetDirsInvalid(t *testing.T) {
+	for _, a := range []AssetDir{
+		{DirGlob: "["},
+		{DirGlob: "static", CommentStyle: "pascal"},
+		{DirGlob: "static", Licenses: []License{{Lines: []string{"("}}}},
+	} {
+		c := &Config{AssetDirs: []AssetDir{a}}
+		if err := c.CompileRegexps(); err == nil {
+			t.Errorf("CompileRegexps(%+v) succeeded, want error", a)
+		}
+	}
+}
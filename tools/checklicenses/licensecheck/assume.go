@@ -0,0 +1,65 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// licenseNamed returns the index in Licenses of the license with the given
+// id, its Name or else its position like "Licenses[1]".
+func (c *Config) licenseNamed(name string) (int, error) {
+	ids := make([]string, 0, len(c.Licenses))
+	for i := range c.Licenses {
+		id := c.Licenses[i].id("Licenses", i)
+		if id == name {
+			return i, nil
+		}
+		ids = append(ids, id)
+	}
+	return -1, fmt.Errorf("no license %q, want one of %s", name, strings.Join(ids, ", "))
+}
+
+// onlyLicense returns a copy of the configuration which accepts nothing but
+// the license at index i of Licenses. The license keeps its id in messages.
+func (c *Config) onlyLicense(i int) *Config {
+	l := c.Licenses[i]
+	l.Name = l.id("Licenses", i)
+	one := *c
+	one.Licenses = []License{l}
+	one.licensesRegexps = c.licensesRegexps[i : i+1]
+	return &one
+}
+
+// assumeLicense checks each of files against the license at index i of
+// Licenses alone, regardless of Accept and Reject, and prints PASS or FAIL
+// with where the header departs from the license. It returns the number of
+// files which fail.
+func (c *Config) assumeLicense(w io.Writer, i int, files []string, opts Options) (int, error) {
+	one := c.onlyLicense(i)
+	failed := 0
+	for _, file := range files {
+		name, contents, err := opts.readContents(file)
+		if err != nil {
+			return failed, err
+		}
+		if contents == nil {
+			return failed, fmt.Errorf("%s is a directory", file)
+		}
+		if one.match(name, contents) {
+			fmt.Fprintf(w, "PASS %s\n", file)
+			continue
+		}
+		failed++
+		if m, ok := one.firstMismatch(name, contents); ok {
+			fmt.Fprintf(w, "FAIL %s: %s\n", file, m)
+		} else {
+			fmt.Fprintf(w, "FAIL %s: header does not match %s\n", file, one.Licenses[0].Name)
+		}
+	}
+	return failed, nil
+}
@@ -0,0 +1,50 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAssumeLicense(t *testing.T) {
+	c := &Config{
+		Licenses: []License{
+			{Name: "BSD", Lines: []string{"^// Copyright 2026 X", "// BSD"}},
+			{Lines: []string{"^// Copyright 2026 X", "// MIT"}},
+		},
+		Accept: []string{`.*\.c`},
+	}
+	if err := c.CompileRegexps(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.licenseNamed("GPL"); err == nil {
+		t.Error("licenseNamed(GPL) succeeded, want error")
+	}
+	i, err := c.licenseNamed("Licenses[1]")
+	if err != nil || i != 1 {
+		t.Fatalf("licenseNamed(Licenses[1]) = %d, %v, want 1, nil", i, err)
+	}
+
+	dir := t.TempDir()
+	bsd, mit := filepath.Join(dir, "bsd.go"), filepath.Join(dir, "mit.go")
+	if err := os.WriteFile(bsd, []byte("// Copyright 2026 X\n// BSD\npackage a\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(mit, []byte("// Copyright 2026 X\n// MIT\npackage a\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	failed, err := c.assumeLicense(&b, i, []string{bsd, mit}, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "FAIL " + bsd + `: line 2 departs from Licenses[1]: got "// BSD", want "// MIT"` + "\nPASS " + mit + "\n"
+	if failed != 1 || b.String() != want {
+		t.Errorf("assumeLicense() = %d, %q, want 1, %q", failed, b.String(), want)
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"sort"
+	"strings"
+)
+
+// readBaseline returns the set of paths recorded in a baseline file. The
+// file lists one path per line; blank lines and lines starting with # are
+// ignored.
+func readBaseline(file string) (map[string]bool, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	paths := map[string]bool{}
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		paths[line] = true
+	}
+	return paths, s.Err()
+}
+
+// writeBaseline records paths in a baseline file, sorted so that the file
+// diffs cleanly when it is checked in.
+func writeBaseline(file string, paths []string) error {
+	sorted := append([]string(nil), paths...)
+	sort.Strings(sorted)
+	var b bytes.Buffer
+	b.WriteString("# Files known to violate the license policy; generated by checklicenses -write-baseline.\n")
+	for _, p := range sorted {
+		b.WriteString(p)
+		b.WriteByte('\n')
+	}
+	return os.WriteFile(file, b.Bytes(), 0o644)
+}
+
+// pruneFixed returns the paths of baseline which are to be kept: those still
+// violating the policy, and those which were not scanned, so that a run
+// checking only some files keeps the others grandfathered.
+func pruneFixed(baseline map[string]bool, still []string, scanned map[string]bool) []string {
+	keep := map[string]bool{}
+	for _, p := range still {
+		keep[p] = true
+	}
+	for p := range baseline {
+		if !scanned[p] {
+			keep[p] = true
+		}
+	}
+	kept := make([]string, 0, len(keep))
+	for p := range keep {
+		kept = append(kept, p)
+	}
+	return kept
+}
@@ -0,0 +1,78 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestBaselineRoundTrip(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "baseline")
+	if err := writeBaseline(file, []string{"b.go", "a.go"}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := readBaseline(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]bool{"a.go": true, "b.go": true}; !reflect.DeepEqual(got, want) {
+		t.Errorf("readBaseline() = %v, want %v", got, want)
+	}
+}
+
+func TestPruneBaselineSubset(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"config.json":       runConfig,
+		"repo/broken.go":    "package a\n",
+		"repo/fixed.go":     "package a\n",
+		"repo/untouched.go": "package a\n",
+	})
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	if err := os.Chdir(filepath.Join(dir, "repo")); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "."},
+		{"-c", "user.name=X", "-c", "user.email=x@example.com", "commit", "-q", "-m", "add"},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	baseline := filepath.Join(dir, "baseline")
+	if err := writeBaseline(baseline, []string{"broken.go", "fixed.go", "untouched.go"}); err != nil {
+		t.Fatal(err)
+	}
+	writeTree(t, ".", map[string]string{
+		"broken.go": "package a\n\nfunc f() {}\n",
+		"fixed.go":  "// Copyright 2026 X\npackage a\n",
+	})
+
+	// Only the modified files are checked; untouched.go stays grandfathered.
+	args := []string{"-c", filepath.Join(dir, "config.json"), "-baseline", baseline, "-prune-baseline", "-status", "M"}
+	if code, stdout, stderr := runFlags(args...); code != exitOK {
+		t.Fatalf("run(%q) = %d, stdout %q, stderr %q; want %d", args, code, stdout, stderr, exitOK)
+	}
+	got, err := readBaseline(baseline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]bool{"broken.go": true, "untouched.go": true}; !reflect.DeepEqual(got, want) {
+		t.Errorf("baseline after pruning = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// errNoBlob is returned for files which are not in the git revision read by
+// a blobReader.
+var errNoBlob = errors.New("not in git")
+
+// errBadRevision is returned for revisions which do not name a commit.
+var errBadRevision = errors.New("invalid revision")
+
+// blobReader reads file contents from the git object database through a
+// single long-running git cat-file --batch, rather than from the working
+// tree.
+type blobReader struct {
+	// prefix turns a path into an object name, e.g. "HEAD:".
+	prefix string
+
+	mu  sync.Mutex
+	cmd *exec.Cmd
+	in  io.WriteCloser
+	out *bufio.Reader
+}
+
+// newBlobReader starts reading files as they are in rev, or in the index if
+// rev is "index".
+func newBlobReader(ctx context.Context, rev string) (*blobReader, error) {
+	prefix := ":"
+	if rev != "index" {
+		if err := exec.CommandContext(ctx, "git", "rev-parse", "--verify", "--quiet", rev+"^{commit}").Run(); err != nil {
+			return nil, fmt.Errorf("-read-from: %w %q", errBadRevision, rev)
+		}
+		prefix = rev + ":"
+	}
+	cmd := exec.CommandContext(ctx, "git", "cat-file", "--batch")
+	in, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("error running git cat-file: %v", err)
+	}
+	return &blobReader{prefix: prefix, cmd: cmd, in: in, out: bufio.NewReader(out)}, nil
+}
+
+// read returns the contents of file, which is relative to the current
+// directory. Files which are not blobs, like submodules, have nil contents.
+func (b *blobReader) read(file string) ([]byte, error) {
+	return b.readAt(b.prefix, file)
+}
+
+// readAt is like read, but reads file as it is in the revision named by
+// prefix, like "HEAD:".
+func (b *blobReader) readAt(prefix, file string) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	// A leading ./ makes git resolve the path relative to the current
+	// directory rather than the top of the repository.
+	if _, err := fmt.Fprintf(b.in, "%s./%s\n", prefix, file); err != nil {
+		return nil, fmt.Errorf("error running git cat-file: %v", err)
+	}
+	header, err := b.out.ReadString('\n')
+	if err != nil {
+		return nil, fmt.Errorf("error running git cat-file: %v", err)
+	}
+	// The header is "<object> <type> <size>", or "<name> missing".
+	fields := strings.Fields(header)
+	if len(fields) != 3 {
+		return nil, fmt.Errorf("%s: %w", file, errNoBlob)
+	}
+	size, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return nil, fmt.Errorf("git cat-file: bad header %q", header)
+	}
+	// The contents are followed by a newline.
+	contents := make([]byte, size+1)
+	if _, err := io.ReadFull(b.out, contents); err != nil {
+		return nil, fmt.Errorf("error running git cat-file: %v", err)
+	}
+	if fields[1] != "blob" {
+		return nil, nil
+	}
+	return contents[:size], nil
+}
+
+// Close stops git cat-file.
+func (b *blobReader) Close() error {
+	b.in.Close()
+	return b.cmd.Wait()
+}
@@ -0,0 +1,69 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"testing"
+)
+
+func TestBlobReader(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name, contents string) {
+		if err := os.WriteFile(name, []byte(contents), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	git("init", "-q")
+	write("a.go", "committed\n")
+	git("add", "a.go")
+	git("commit", "-q", "-m", "a")
+	write("a.go", "staged\n")
+	git("add", "a.go")
+	write("a.go", "working tree\n")
+
+	ctx := context.Background()
+	for rev, want := range map[string]string{
+		"HEAD":  "committed\n",
+		"index": "staged\n",
+	} {
+		b, err := newBlobReader(ctx, rev)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := b.read("a.go")
+		if err != nil || string(got) != want {
+			t.Errorf("%s: read(a.go) = %q, %v, want %q", rev, got, err, want)
+		}
+		if _, err := b.read("missing.go"); !errors.Is(err, errNoBlob) {
+			t.Errorf("%s: read(missing.go) = %v, want %v", rev, err, errNoBlob)
+		}
+		if err := b.Close(); err != nil {
+			t.Errorf("%s: Close() = %v", rev, err)
+		}
+	}
+	if _, err := newBlobReader(ctx, "nosuchrev"); !errors.Is(err, errBadRevision) {
+		t.Errorf("newBlobReader(nosuchrev) = %v, want %v", err, errBadRevision)
+	}
+}
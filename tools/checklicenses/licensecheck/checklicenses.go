@@ -0,0 +1,894 @@
+// Copyright 2017-2018 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package licensecheck checks that files carry an acceptable license header,
+// under a configuration loaded from JSON. Scan checks the files of a tree and
+// Check a single file; Main runs the checklicenses command.
+package licensecheck
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Exit codes.
+const (
+	exitOK          = 0
+	exitViolations  = 1
+	exitUsage       = 2
+	exitIO          = 3
+	exitTimeout     = 4
+	exitInterrupted = 130
+)
+
+// flags are the command line flags of Main.
+var flags = flag.NewFlagSet("checklicenses", flag.ContinueOnError)
+
+var (
+	absPath      = flags.Bool("a", false, "Print absolute paths")
+	relativeTo   = flags.String("relative-to", "", "Print paths relative to this directory, overriding -a and GoPkg trimming")
+	configFile   = flags.String("c", "", "Configuration file in JSON format")
+	strictEnv    = flags.Bool("strict-env", false, "Fail if the configuration refers to unset environment variables")
+	zeroOnConfig = flags.Bool("exit-zero-on-config-error", false, "Exit 0 with a warning instead of 2 when the configuration is invalid; only meant for rolling out a new configuration, as it hides every violation")
+	ignoreFile   = flags.String("ignore-file", "", "File of gitignore patterns for files to skip, added to Reject")
+	progressOn   = flags.Bool("progress", false, "Periodically print the number of scanned files to stderr")
+	verbose      = flags.Bool("v", false, "Print a summary of the run to stderr")
+	skipGen      = flags.Bool("skip-generated", false, "Skip files marked linguist-generated in .gitattributes")
+	status       = flags.String("status", "", "Only check files with one of these git status letters relative to -status-base, e.g. A or ACM")
+	statusBase   = flags.String("status-base", "HEAD", "Revision the -status filter compares against")
+	prRange      = flags.String("pr", "", "Only check the files a pull request changes without deleting them, given as the revision range base..head")
+	gitRetries   = flags.Int("git-retries", 0, "Retry failing git commands this many times, for CI machines where they fail transiently")
+	gitBackoff   = flags.Duration("git-backoff", 250*time.Millisecond, "Wait before the first -git-retries retry, twice as long before each next")
+	walkDir      = flags.String("walk", "", "Check the files under this directory instead of those added to git, for trees outside of a git repository; the rules and reports take their paths relative to it")
+	scanHidden   = flags.Bool("scan-hidden", false, "With -walk, also check the files whose name or directory starts with a dot, like .github/; .git is never checked")
+	untracked    = flags.Bool("include-untracked", false, "Also check files which are not added to git yet, unless they are ignored")
+	timeout      = flags.Duration("timeout", 0, "Stop after this long, printing the violations found so far and the files still pending")
+	jobs         = flags.Int("j", 0, "Check this many files concurrently; the report does not depend on it (default from -jobs-from-env, or the CPU quota of the cgroup, or the number of CPUs)")
+	jobsEnv      = flags.String("jobs-from-env", "NPROC", "Environment variable giving the default of -j, as CI machines set it; empty to ignore the environment")
+	readKiB      = flags.Int("read-kib", 64, "Only read the first this many KiB of each file, where headers are, or whole files if 0; -decompress reads .gz files whole")
+	fileTimeout  = flags.Duration("file-timeout", 0, "Give up on files taking longer than this to check, and report them as errors")
+	readFrom     = flags.String("read-from", "", `Read contents from git instead of the working tree: "index" for the staged contents, or a revision like HEAD`)
+	decompress   = flags.Bool("decompress", false, "Check the decompressed contents of .gz files")
+	traceRules   = flags.String("trace-rules", "", "Write the rule deciding whether each listed file is checked to this file, as JSON")
+	maxFiles     = flags.Int("max-files", 0, "Refuse to check more than this many files (0 means no limit)")
+	versionsOn   = flags.Bool("license-report", false, "Print the number of checked files carrying each license and Version to stderr")
+	requireUsed  = flags.Bool("require-all-licenses-used", false, "Fail the run if one of the configured Licenses is carried by none of the checked files, as it is probably obsolete")
+	profileConf  = flags.Bool("profile-config", false, "Print how often each license of each configuration, including those of directories and AssetDirs, matched and the time spent matching it to stderr")
+	assumeName   = flags.String("assume-license", "", "Check only the files given as arguments, against only this one of the configured licenses, and print where they depart from it")
+	depsOn       = flags.Bool("deps", false, "Print the license of each module the build depends on, from vendor/modules.txt or go list, and exit; fails if one is not in DepLicenses")
+	checkStdin   = flags.Bool("check-stdin", false, "Check the contents read from stdin as those of the -name file, print the result as JSON like -serve, and exit")
+	stdinName    = flags.String("name", "", "Path the -check-stdin contents are checked as, which also selects their comment style")
+	serveAddr    = flags.String("serve", "", `Serve requests to check a file, POSTed as JSON {"path", "contents"} to /check, on this address, like localhost:8080 or unix:/path/to/socket`)
+	configHash   = flags.Bool("config-hash", false, "Print a hash of the effective configuration and exit")
+	explainConf  = flags.Bool("explain-config", false, "Describe how the loaded rules are applied, in order, and exit")
+	printConf    = flags.Bool("print-config", false, "Print the effective configuration as JSON, usable with -c, and exit")
+	listFiles    = flags.Bool("list-files", false, "Print the files which would be checked, without reading them, and exit")
+	discoverOn   = flags.Bool("discover", false, "Print the distinct headers of the selected files, with their number of files and a sample, and exit")
+	sampleHeader = flags.Bool("sample-by-header", false, "Check one sample file per distinct header, print the results attributed to each group, and exit; an approximation for audits, not for CI")
+	discoverRNG  = flags.Int64("deterministic-seed", 0, "Pick the -discover and -sample-by-header samples at random from this seed, rather than the smallest path")
+	compareTo    = flags.String("compare-two-configs", "", "Print the files whose result would change if this configuration replaced -c, and exit")
+	allowEmpty   = flags.Bool("allow-empty", false, "Succeed even if no files were selected for checking")
+
+	baselineFile  = flags.String("baseline", "", "File listing known violations which do not fail the run")
+	writeBaseFile = flags.Bool("write-baseline", false, "Record the current violations in the -baseline file and exit")
+	pruneBaseline = flags.Bool("prune-baseline", false, "Remove fixed files from the -baseline file, keeping those this run did not check, e.g. outside of -status")
+
+	fix      = flags.Bool("fix", false, "Insert the header template into files without a license, fix formatting and comment style, and collapse duplicate headers")
+	template = flags.String("template", "", "Header template for -fix (default "+defaultTemplate+" at the repository root), for files the configured HeaderTemplates do not cover")
+	fixOnly  = flags.String("fix-only", "", "Only fix violations in these comma separated buckets with -fix (default all fixable)")
+	dryRun   = flags.Bool("dry-run", false, "Print the files -fix would change, without changing them")
+	holder   = flags.String("holder", "", "Copyright holder substituted for {{holder}} in the header template (default the configured Owner)")
+
+	severities   = flags.String("severity", "", "Comma separated bucket=severity pairs overriding the configured Severity in reports")
+	noFail       = flags.Bool("no-fail", false, "Report violations but exit 0 in spite of them")
+	failOn       = flags.String("fail-on", "", "Only fail the run on violations in these comma separated buckets (default all)")
+	firstCommit  = flags.Bool("check-first-commit", false, "Also report files which were added without a license, even if they carry one now; reads each file as first committed, so best combined with -status or -pr")
+	gitYears     = flags.Bool("git-years", false, "Report files whose copyright years do not span the years of their first and last git commits; runs git log over the whole history")
+	strictSingle = flags.Bool("strict-single-license", false, "Report files matching more than one of the configured licenses as ambiguous")
+	showMismatch = flags.Bool("show-mismatch", false, "For files missing a license, report the first line where the header departs from the most similar license")
+	postHook     = flags.String("post-hook", "", "Command run after the scan, with the exit code as last argument and the -summary-json summary on stdin")
+	htmlFile     = flags.String("html", "", "Write a self-contained HTML page with the summary, the violations by bucket and directory, and the files carrying each license to this file")
+	summaryJSON  = flags.String("summary-json", "", "Write a compact summary of the run, with a versioned schema for trend tracking, to this file")
+	cacheFile    = flags.String("cache", "", "Reuse the results recorded in this file of the files which did not change since the run writing it, under the same -config-hash; rewritten after each run")
+	quiet        = flags.Bool("quiet-unless-changed", false, "Print nothing and exit 0 if the violations are those the -cache recorded of the last run")
+	diffstat     = flags.Bool("diffstat", false, "Print the number of violations by top-level directory and by extension instead of listing them")
+	stream       = flags.Bool("stream", false, "Print the violations of each file as soon as it and the files before it are scanned, in input order")
+	format       = flags.String("format", "text", fmt.Sprintf("Report format, one of %v", formats))
+	only         = flags.String("only", "", fmt.Sprintf("Only print violations in these comma separated buckets %v", buckets))
+)
+
+// Main runs the checklicenses command with the command line args, printing
+// reports to stdout and everything else to stderr, and returns the exit code.
+// It parses the flags into package state, so calls must not overlap.
+func Main(args []string, stdout, stderr io.Writer) int {
+	log.SetOutput(stderr)
+	flags.SetOutput(stderr)
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+
+	if *prRange != "" && (*status != "" || !strings.Contains(*prRange, "..")) {
+		log.Print("-pr takes a revision range base..head, and does not work with -status")
+		return exitUsage
+	}
+
+	if *walkDir != "" && (*status != "" || *prRange != "" || *untracked || *readFrom != "" || *skipGen || *gitYears || *firstCommit) {
+		log.Print("-walk does not work with the options reading git: -status, -pr, -include-untracked, -read-from, -skip-generated, -git-years and -check-first-commit")
+		return exitUsage
+	}
+
+	if *requireUsed && (*status != "" || *prRange != "") {
+		log.Print("-require-all-licenses-used needs all files checked, and does not work with -status or -pr")
+		return exitUsage
+	}
+
+	if *jobs < 0 || *readKiB < 0 {
+		log.Print("-j and -read-kib cannot be negative")
+		return exitUsage
+	}
+	if *jobs == 0 {
+		n, err := defaultJobs(*jobsEnv, cgroupRoot)
+		if err != nil {
+			log.Print(err)
+			return exitUsage
+		}
+		*jobs = n
+	}
+
+	if *gitRetries < 0 {
+		log.Print("-git-retries cannot be negative")
+		return exitUsage
+	}
+
+	if *writeBaseFile && *baselineFile == "" {
+		log.Print("-write-baseline requires -baseline")
+		return exitUsage
+	}
+
+	var onlyBuckets map[bucket]bool
+	if *only != "" {
+		var err error
+		if onlyBuckets, err = parseBuckets(*only); err != nil {
+			log.Print(err)
+			return exitUsage
+		}
+	}
+
+	var fixBuckets map[bucket]bool
+	if *fixOnly != "" || *dryRun {
+		if !*fix {
+			log.Print("-fix-only and -dry-run require -fix")
+			return exitUsage
+		}
+	}
+	if *fixOnly != "" {
+		var err error
+		if fixBuckets, err = parseBuckets(*fixOnly); err != nil {
+			log.Print(err)
+			return exitUsage
+		}
+		for b := range fixBuckets {
+			if !fixable(b) {
+				log.Printf("-fix-only: cannot fix %s violations", b)
+				return exitUsage
+			}
+		}
+	}
+
+	var failBuckets map[bucket]bool
+	if *failOn != "" {
+		var err error
+		if failBuckets, err = parseBuckets(*failOn); err != nil {
+			log.Print(err)
+			return exitUsage
+		}
+	}
+
+	if err := checkFormat(*format); err != nil {
+		log.Print(err)
+		return exitUsage
+	}
+	if *stream && (*format != "text" || *fix || *writeBaseFile) {
+		log.Print("-stream only works with -format text, and without -fix or -write-baseline")
+		return exitUsage
+	}
+	if *quiet && (*cacheFile == "" || *stream) {
+		log.Print("-quiet-unless-changed requires -cache, and does not work with -stream")
+		return exitUsage
+	}
+	if *diffstat && (*format != "text" || *stream) {
+		log.Print("-diffstat only works with -format text, and without -stream")
+		return exitUsage
+	}
+
+	if *checkStdin != (*stdinName != "") {
+		log.Print("-check-stdin and -name go together")
+		return exitUsage
+	}
+
+	if *relativeTo != "" {
+		dir, err := filepath.Abs(*relativeTo)
+		if err != nil {
+			log.Printf("invalid -relative-to: %v", err)
+			return exitUsage
+		}
+		*relativeTo = dir
+	}
+
+	config, err := loadConfig(*configFile)
+	if err != nil {
+		return configError(err)
+	}
+
+	if *severities != "" {
+		m, err := parseSeverities(*severities)
+		if err != nil {
+			log.Printf("-severity: %v", err)
+			return exitUsage
+		}
+		if config.Severity == nil {
+			config.Severity = map[bucket]string{}
+		}
+		for b, sev := range m {
+			config.Severity[b] = sev
+		}
+	}
+
+	var ignorePatterns []string
+	if *ignoreFile != "" {
+		ignorePatterns, err = readIgnoreFile(*ignoreFile)
+		if err == nil {
+			err = config.addIgnore(ignorePatterns)
+		}
+		if err != nil {
+			return configError(fmt.Errorf("-ignore-file: %v", err))
+		}
+	}
+
+	if *depsOn {
+		return checkDeps(ctx, stdout, *format, config)
+	}
+
+	opts := flagOptions()
+	if *checkStdin {
+		return checkReader(os.Stdin, stdout, *stdinName, config, opts)
+	}
+
+	if *serveAddr != "" {
+		if err := serve(ctx, *serveAddr, config, opts); err != nil {
+			log.Printf("-serve: %v", err)
+			return exitIO
+		}
+		return exitOK
+	}
+
+	if *explainConf {
+		config.explain(stdout)
+		return exitOK
+	}
+
+	if *printConf {
+		b, err := json.MarshalIndent(config.resolved(), "", "\t")
+		if err != nil {
+			log.Print(err)
+			return exitUsage
+		}
+		fmt.Fprintf(stdout, "%s\n", b)
+		return exitOK
+	}
+
+	if *configHash {
+		h, err := config.hash(currentPolicyOptions())
+		if err != nil {
+			log.Print(err)
+			return exitUsage
+		}
+		fmt.Fprintln(stdout, h)
+		return exitOK
+	}
+
+	if *assumeName != "" {
+		if flags.NArg() == 0 {
+			log.Print("-assume-license requires files to check")
+			return exitUsage
+		}
+		i, err := config.licenseNamed(*assumeName)
+		if err != nil {
+			log.Printf("-assume-license: %v", err)
+			return exitUsage
+		}
+		failed, err := config.assumeLicense(stdout, i, flags.Args(), opts)
+		if err != nil {
+			log.Print(err)
+			return exitIO
+		}
+		if failed > 0 {
+			return exitViolations
+		}
+		return exitOK
+	}
+
+	now := time.Now()
+	var templates *headerTemplates
+	if *fix {
+		h := *holder
+		if h == "" {
+			h = config.Owner
+		}
+		if templates, err = loadTemplates(ctx, config, *template, h, now.Year()); err != nil {
+			log.Print(err)
+			return exitUsage
+		}
+	}
+
+	var baseline map[string]bool
+	if *baselineFile != "" && !*writeBaseFile {
+		if baseline, err = readBaseline(*baselineFile); err != nil {
+			log.Print(err)
+			return exitIO
+		}
+	}
+
+	var incorrect []violation
+	sum := summary{buckets: map[bucket]int{}}
+
+	// List files added to u-root.
+	var lister FileLister = gitLister{untracked: *untracked}
+	leaveWalk := func() {}
+	if *walkDir != "" {
+		if leaveWalk, err = enterWalk(*walkDir); err != nil {
+			log.Print(err)
+			return exitIO
+		}
+		defer leaveWalk()
+		lister = walkLister{dir: ".", hidden: *scanHidden}
+	}
+	files, err := lister.ListFiles(ctx)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			log.Printf("timed out after %v listing files", *timeout)
+			return exitTimeout
+		}
+		if ctx.Err() != nil {
+			return exitInterrupted
+		}
+		log.Print(err)
+		return exitIO
+	}
+
+	sum.listed = len(files)
+	if *prRange != "" {
+		// Check the files the range adds or changes, as if by
+		// -status-base <range> -status d.
+		*status, *statusBase = "d", *prRange
+	}
+	if *status != "" {
+		changed, err := gitChanged(ctx, *statusBase, *status)
+		if err != nil {
+			log.Print(err)
+			return exitIO
+		}
+		files = intersect(files, changed)
+	}
+
+	// Select the files to check.
+	if *profileConf {
+		config.enableProfile()
+	}
+	cache := newConfigCache(config, opts)
+	cache.trace = *traceRules != ""
+	cache.profile = *profileConf
+	candidates, skipped, err := cache.selectFiles(files)
+	if err != nil {
+		return configError(err)
+	}
+	sum.skippedDirs = skipped
+	if *traceRules != "" {
+		if err := writeTrace(*traceRules, cache.traced); err != nil {
+			log.Print(err)
+			return exitIO
+		}
+	}
+
+	if *compareTo != "" {
+		other, err := loadConfig(*compareTo)
+		if err == nil {
+			err = other.addIgnore(ignorePatterns)
+		}
+		if err != nil {
+			log.Printf("-compare-two-configs: %v", err)
+			return exitUsage
+		}
+		otherCandidates, _, err := newConfigCache(other, opts).selectFiles(files)
+		if err != nil {
+			log.Print(err)
+			return exitUsage
+		}
+		changes, err := compareCandidates(ctx, candidates, otherCandidates)
+		if err != nil {
+			if ctx.Err() != nil {
+				return exitInterrupted
+			}
+			log.Print(err)
+			return exitIO
+		}
+		printChanges(stdout, changes, func(file string) string {
+			return displayPath(config, file)
+		})
+		return exitOK
+	}
+
+	if *skipGen && len(candidates) > 0 {
+		names := make([]string, 0, len(candidates))
+		for _, c := range candidates {
+			names = append(names, c.file)
+		}
+		gen, err := gitGenerated(ctx, names)
+		if err != nil {
+			log.Print(err)
+			return exitIO
+		}
+		kept := candidates[:0]
+		for _, c := range candidates {
+			if gen[c.file] {
+				sum.skippedGenerated++
+				continue
+			}
+			kept = append(kept, c)
+		}
+		candidates = kept
+	}
+
+	if *listFiles {
+		for _, c := range candidates {
+			fmt.Fprintln(stdout, displayPath(config, c.file))
+		}
+		return exitOK
+	}
+
+	if *discoverOn {
+		groups, err := discover(candidates, false)
+		if err != nil {
+			log.Print(err)
+			return exitIO
+		}
+		printDiscovered(stdout, groups, *discoverRNG, func(file string) string {
+			return displayPath(config, file)
+		})
+		return exitOK
+	}
+
+	if *sampleHeader {
+		groups, err := sampleByHeader(ctx, candidates, *discoverRNG, opts.FileTimeout)
+		if err != nil {
+			if ctx.Err() != nil {
+				return exitInterrupted
+			}
+			log.Print(err)
+			return exitIO
+		}
+		printSampled(stdout, groups, func(file string) string {
+			return displayPath(config, file)
+		})
+		return exitOK
+	}
+
+	// Checking nothing is almost always a misconfiguration, which must not
+	// pass silently. Only when checking changed files is it expected.
+	if len(candidates) == 0 && !*allowEmpty && *status == "" {
+		log.Print("no files selected for checking; check GoPkg, Accept and Reject, or pass -allow-empty")
+		return exitUsage
+	}
+
+	if *maxFiles > 0 && len(candidates) > *maxFiles {
+		log.Printf("%d files selected for checking, more than -max-files %d", len(candidates), *maxFiles)
+		return exitUsage
+	}
+
+	// Files need the years of their commits if -git-years or their
+	// configuration asks for it.
+	var needYears []int
+	for i := range candidates {
+		if *gitYears || (candidates[i].config.GitYears && *walkDir == "") {
+			needYears = append(needYears, i)
+		}
+	}
+	if len(needYears) > 0 {
+		first, last, err := gitCommitYears(ctx)
+		if err != nil {
+			log.Print(err)
+			return exitIO
+		}
+		for _, i := range needYears {
+			f := candidates[i].file
+			candidates[i].firstYear, candidates[i].lastYear = first[f], last[f]
+		}
+	}
+
+	if *firstCommit {
+		added, err := gitFirstAdded(ctx)
+		if err != nil {
+			log.Print(err)
+			return exitIO
+		}
+		history, err := newBlobReader(ctx, "HEAD")
+		if err != nil {
+			log.Print(err)
+			return exitIO
+		}
+		defer history.Close()
+		for i := range candidates {
+			candidates[i].addedIn, candidates[i].history = added[candidates[i].file], history
+		}
+	}
+
+	if *readFrom != "" {
+		blobs, err := newBlobReader(ctx, *readFrom)
+		switch {
+		case err == nil:
+			defer blobs.Close()
+			for i := range candidates {
+				candidates[i].blobs = blobs
+			}
+		case errors.Is(err, errBadRevision):
+			log.Print(err)
+			return exitUsage
+		default:
+			log.Printf("%v; reading the working tree instead", err)
+		}
+	}
+
+	var prevCache, nextCache *resultCache
+	if *cacheFile != "" {
+		if prevCache, err = readCache(*cacheFile); err != nil {
+			log.Print(err)
+			return exitIO
+		}
+		h, err := config.hash(currentPolicyOptions())
+		if err == nil {
+			err = cacheCandidates(candidates, prevCache, h)
+		}
+		if err != nil {
+			log.Print(err)
+			return exitIO
+		}
+		// The results of the files not checked this time, e.g. outside
+		// of -status, stay recorded while they are listed.
+		nextCache = &resultCache{Schema: cacheSchema, ConfigHash: h, Files: map[string]cachedResult{}}
+		if prevCache.valid(h) {
+			for _, f := range files {
+				if r, ok := prevCache.Files[f]; ok {
+					nextCache.Files[f] = r
+				}
+			}
+		}
+	}
+
+	// Iterate over files.
+	prog := newProgress(*progressOn, stderr, len(candidates))
+	// stopped ends a scan cut short by a signal or the -timeout, with the
+	// pending files not yet checked.
+	stopped := func(pending []candidate) int {
+		prog.Stop()
+		if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			log.Print("interrupted")
+			return exitInterrupted
+		}
+		log.Printf("timed out after %v with %d of %d files pending:", *timeout, len(pending), len(candidates))
+		for _, c := range pending {
+			fmt.Fprintf(stderr, "\t%s\n", displayPath(config, c.file))
+		}
+		if err := writeReport(stdout, *format, config, filterBuckets(incorrect, onlyBuckets), now); err != nil {
+			log.Print(err)
+		}
+		return exitTimeout
+	}
+	var streamed *streamPrinter
+	if *stream {
+		streamed = &streamPrinter{w: stdout, config: config, only: onlyBuckets, baseline: baseline}
+	}
+	licenses := licenseCounts{}
+	// scanned are the paths which -prune-baseline may remove, the files
+	// checked without an error and the directories holding the files.
+	scanned := map[string]bool{}
+	err = cache.scan(ctx, candidates, func(v violation, err error) {
+		prog.Done()
+		sum.checked++
+		if nextCache != nil {
+			delete(nextCache.Files, v.file)
+			if err == nil && v.resultKey != "" {
+				nextCache.Files[v.file] = newCachedResult(v)
+			}
+		}
+		if err != nil {
+			log.Print(err)
+			sum.errors++
+			return
+		}
+		if *pruneBaseline {
+			scanned[config.trimPath(v.file)] = true
+		}
+		if v.skipped != "" {
+			sum.skippedContent++
+			if *verbose {
+				log.Printf("skipped %s: matches %s", displayPath(config, v.file), v.skipped)
+			}
+			return
+		}
+		sum.count(v.file, v.bucket != "")
+		if opts.countLicenses {
+			licenses.add(v)
+		}
+		if v.bucket != "" {
+			incorrect = append(incorrect, v)
+			streamed.print(v)
+		}
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return stopped(candidates[sum.checked:])
+		}
+		prog.Stop()
+		log.Print(err)
+		return exitIO
+	}
+	prog.Stop()
+	sum.passed = sum.checked - sum.errors - sum.skippedContent - len(incorrect)
+
+	dist, err := config.checkDistFiles(files)
+	if err != nil {
+		log.Print(err)
+		return exitIO
+	}
+	dirs := config.checkDirRules(files)
+	if *pruneBaseline {
+		for dir := range filesByDir(files) {
+			scanned[config.trimPath(dir)] = true
+		}
+	}
+	for _, v := range append(dist, dirs...) {
+		incorrect = append(incorrect, v)
+		streamed.print(v)
+	}
+	sum.violations = len(incorrect)
+
+	if *fix {
+		// Files are fixed in the comment syntax of the configuration
+		// which checked them, like that of their AssetDirs.
+		configs := make(map[string]*Config, len(candidates))
+		for _, c := range candidates {
+			configs[c.file] = c.config
+		}
+		kept := incorrect[:0]
+		for _, v := range incorrect {
+			if !fixable(v.bucket) || (fixBuckets != nil && !fixBuckets[v.bucket]) {
+				kept = append(kept, v)
+				continue
+			}
+			if err := fixFile(v, configs[v.file], templates, *dryRun); err != nil {
+				log.Print(err)
+				kept = append(kept, v)
+				continue
+			}
+			if *dryRun {
+				fmt.Fprintf(stderr, "would fix %s: %s\n", config.trimPath(v.file), v.bucket)
+				kept = append(kept, v)
+				continue
+			}
+			sum.fixed++
+		}
+		incorrect = kept
+	}
+
+	trimmed := make([]string, 0, len(incorrect))
+	for _, v := range incorrect {
+		trimmed = append(trimmed, config.trimPath(v.file))
+	}
+	if *writeBaseFile {
+		if err := writeBaseline(*baselineFile, trimmed); err != nil {
+			log.Print(err)
+			return exitIO
+		}
+		return exitOK
+	}
+	if *baselineFile != "" {
+		var fresh []violation
+		var still []string
+		for i, p := range trimmed {
+			if baseline[p] {
+				still = append(still, p)
+			} else {
+				fresh = append(fresh, incorrect[i])
+			}
+		}
+		sum.baselined = len(still)
+		if *pruneBaseline {
+			if kept := pruneFixed(baseline, still, scanned); len(kept) < len(baseline) {
+				if err := writeBaseline(*baselineFile, kept); err != nil {
+					log.Print(err)
+					return exitIO
+				}
+			}
+		}
+		incorrect = fresh
+	}
+	failing := 0
+	for _, v := range incorrect {
+		sum.buckets[v.bucket]++
+		if !config.enforced(v, now) {
+			sum.pending++
+		} else if failBuckets == nil || failBuckets[v.bucket] {
+			failing++
+		}
+	}
+
+	unchanged := false
+	if nextCache != nil {
+		nextCache.Violations = violationSet(config, filterBuckets(incorrect, onlyBuckets))
+		unchanged = *quiet && nextCache.sameViolations(prevCache)
+		if err := writeCache(*cacheFile, nextCache); err != nil {
+			log.Print(err)
+			return exitIO
+		}
+	}
+
+	// Print files with incorrect licenses, unless -stream already did.
+	switch {
+	case unchanged:
+	case *diffstat:
+		config.printDiffstat(stdout, filterBuckets(incorrect, onlyBuckets))
+	case !*stream:
+		if err := writeReport(stdout, *format, config, filterBuckets(incorrect, onlyBuckets), now); err != nil {
+			log.Print(err)
+			return exitIO
+		}
+	}
+	if *verbose {
+		sum.print(stderr)
+	}
+	if *summaryJSON != "" {
+		h, err := config.hash(currentPolicyOptions())
+		if err == nil {
+			err = sum.writeJSON(*summaryJSON, h, now)
+		}
+		if err != nil {
+			log.Print(err)
+			return exitIO
+		}
+	}
+	if *htmlFile != "" {
+		if err := writeHTML(*htmlFile, config, &sum, filterBuckets(incorrect, onlyBuckets), licenses, now); err != nil {
+			log.Print(err)
+			return exitIO
+		}
+	}
+	if *profileConf {
+		cache.printProfile(stderr, *configFile)
+	}
+	if *versionsOn {
+		licenses.print(stderr)
+	}
+	var unused []string
+	if *requireUsed {
+		if unused = licenses.unused(config); len(unused) > 0 {
+			log.Printf("no checked file carries %d of the configured licenses, which are probably obsolete:", len(unused))
+			for _, id := range unused {
+				fmt.Fprintf(stderr, "\t%s\n", id)
+			}
+		}
+	}
+	code := exitOK
+	switch {
+	case sum.errors > 0:
+		code = exitIO
+	case (failing > 0 && !unchanged || len(unused) > 0) && !*noFail:
+		code = exitViolations
+	}
+	if *postHook != "" {
+		// The hook runs where checklicenses was started.
+		leaveWalk()
+		h, err := config.hash(currentPolicyOptions())
+		if err == nil {
+			err = runHook(ctx, *postHook, &sum, h, now, code)
+		}
+		if err != nil {
+			log.Printf("-post-hook: %v", err)
+		}
+	}
+	return code
+}
+
+// enterWalk changes into dir for -walk, so that the files are listed, matched
+// against the rules, read and reported relative to it, as they are relative
+// to the top of the repository with git. The files the flags name which are
+// only written after that are made absolute first. It returns a function
+// changing back.
+func enterWalk(dir string) (func(), error) {
+	for _, f := range []*string{traceRules, baselineFile, htmlFile, summaryJSON, cacheFile} {
+		if *f == "" {
+			continue
+		}
+		abs, err := filepath.Abs(*f)
+		if err != nil {
+			return nil, err
+		}
+		*f = abs
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chdir(dir); err != nil {
+		return nil, fmt.Errorf("-walk: %v", err)
+	}
+	return func() { os.Chdir(wd) }, nil
+}
+
+// flagOptions returns the Options given on the command line.
+func flagOptions() Options {
+	return Options{
+		Jobs:                *jobs,
+		FileTimeout:         *fileTimeout,
+		ReadLimit:           int64(*readKiB) << 10,
+		Decompress:          *decompress,
+		StrictSingleLicense: *strictSingle,
+		ShowMismatch:        *showMismatch,
+		logMasked:           *verbose,
+		// The run counts the files carrying each license for the
+		// reports of them.
+		countLicenses: *versionsOn || *requireUsed || *htmlFile != "",
+	}
+}
+
+// configError logs err, an invalid configuration, and returns exitUsage, or
+// exitOK with -exit-zero-on-config-error.
+func configError(err error) int {
+	log.Print(err)
+	if *zeroOnConfig {
+		log.Print("WARNING: the configuration is invalid and nothing was checked; exiting 0 because of -exit-zero-on-config-error")
+		return exitOK
+	}
+	return exitUsage
+}
+
+// displayPath returns file as it is printed in reports.
+func displayPath(config *Config, file string) string {
+	switch {
+	case *relativeTo != "":
+		abs, err := filepath.Abs(file)
+		if err != nil {
+			return file
+		}
+		rel, err := filepath.Rel(*relativeTo, abs)
+		if err != nil {
+			return abs
+		}
+		return rel
+	case *absPath:
+		return file
+	}
+	return config.trimPath(file)
+}
@@ -0,0 +1,126 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// runFlags is Main with the flags reset to their defaults first, as each test
+// parses its own command line into the same flag variables.
+func runFlags(args ...string) (code int, stdout, stderr string) {
+	flags.VisitAll(func(f *flag.Flag) {
+		f.Value.Set(f.DefValue)
+	})
+	var out, errOut bytes.Buffer
+	code = Main(args, &out, &errOut)
+	return code, out.String(), errOut.String()
+}
+
+// writeTree writes files, mapping names to contents, under dir.
+func writeTree(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, contents := range files {
+		name = filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte(contents), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+const runConfig = `{"licenses": [["^// Copyright \\d+ X"]], "accept": [".*\\.go"]}`
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"config.json":      runConfig,
+		"invalid.json":     `{"licenses": [["("]]}`,
+		"vendor.json":      `{"licenses": [["^// Copyright \\d+ X"]], "accept": [".*\\.go"], "reject": ["vendor/.*"], "dirrules": [{"dirglob": "lib", "requireoneof": ["LICENSE"]}]}`,
+		"tree/a.go":        "// Copyright 2026 X\npackage a\n",
+		"tree/vendor/b.go": "package b\n",
+		"tree/lib/c.go":    "// Copyright 2026 X\npackage c\n",
+		"clean/a.go":       "// Copyright 2026 X\npackage a\n",
+		"dirty/a.go":       "// Copyright 2026 X\npackage a\n",
+		"dirty/b.go":       "package a\n",
+		"nogit/a.go":       "package a\n",
+	})
+	config := filepath.Join(dir, "config.json")
+	for _, tt := range []struct {
+		name   string
+		dir    string
+		args   []string
+		code   int
+		stdout string
+		stderr string
+	}{
+		{name: "clean", args: []string{"-c", config, "-walk", filepath.Join(dir, "clean")}, code: exitOK},
+		{name: "violations", args: []string{"-c", config, "-walk", filepath.Join(dir, "dirty")}, code: exitViolations, stdout: "b.go\n"},
+		{name: "walk rules", args: []string{"-c", filepath.Join(dir, "vendor.json"), "-walk", filepath.Join(dir, "tree")}, code: exitViolations, stdout: "lib\n"},
+		{name: "no fail", args: []string{"-c", config, "-walk", filepath.Join(dir, "dirty"), "-no-fail"}, code: exitOK, stdout: "b.go\n"},
+		{name: "config error", args: []string{"-c", filepath.Join(dir, "invalid.json"), "-walk", filepath.Join(dir, "clean")}, code: exitUsage, stderr: "failed to compile regexps"},
+		{name: "usage", args: []string{"-c", config, "-walk", dir, "-status", "A"}, code: exitUsage, stderr: "-walk does not work"},
+		{name: "git failure", dir: filepath.Join(dir, "nogit"), args: []string{"-c", config}, code: exitIO, stderr: "git"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.dir != "" {
+				wd, err := os.Getwd()
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer os.Chdir(wd)
+				if err := os.Chdir(tt.dir); err != nil {
+					t.Fatal(err)
+				}
+			}
+			code, stdout, stderr := runFlags(tt.args...)
+			if code != tt.code || stdout != tt.stdout || !strings.Contains(stderr, tt.stderr) {
+				t.Errorf("run(%q) = %d, stdout %q, stderr %q; want %d, stdout %q, stderr containing %q", tt.args, code, stdout, stderr, tt.code, tt.stdout, tt.stderr)
+			}
+		})
+	}
+}
+
+func TestRunInterrupted(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{"config.json": runConfig})
+	// The scan blocks reading the FIFO until the test is done. Checks
+	// are only abandoned on SIGINT with a -file-timeout.
+	fifo := blockingFIFO(t, "fifo.go")
+	args := []string{"-c", filepath.Join(dir, "config.json"), "-walk", filepath.Dir(fifo), "-file-timeout", "1h"}
+
+	// Take the signals first, so that those sent before run handles them
+	// do not kill the test.
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt)
+	defer signal.Stop(sig)
+
+	done := make(chan int)
+	go func() {
+		code, _, _ := runFlags(args...)
+		done <- code
+	}()
+	for {
+		syscall.Kill(os.Getpid(), syscall.SIGINT)
+		select {
+		case code := <-done:
+			if code != exitInterrupted {
+				t.Errorf("run(%q) = %d after SIGINT, want %d", args, code, exitInterrupted)
+			}
+			return
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+)
+
+// checkReader checks the contents read from r as those of file, for
+// -check-stdin, and writes the result to w as a serveResult. Missing
+// licenses are detailed as with -show-mismatch. It returns exitViolations if
+// the contents do not conform.
+func checkReader(r io.Reader, w io.Writer, file string, config *Config, opts Options) int {
+	contents, err := io.ReadAll(r)
+	if err != nil {
+		log.Printf("-check-stdin: %v", err)
+		return exitIO
+	}
+	if contents == nil {
+		// Nil contents would be read from file.
+		contents = []byte{}
+	}
+	opts.ShowMismatch = true
+	res, selected, err := config.Check(file, contents, opts)
+	result := newServeResult(file, res, selected, err)
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		log.Printf("-check-stdin: %v", err)
+		return exitIO
+	}
+	switch {
+	case result.Error != "":
+		return exitIO
+	case result.Bucket != "" && !*noFail:
+		return exitViolations
+	}
+	return exitOK
+}
@@ -0,0 +1,80 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestCheckReader(t *testing.T) {
+	c := &Config{Licenses: []License{{Lines: []string{"^Copyright 2026 X\nBSD"}}}, StripComments: true, Accept: []string{`.*\.(go|sh)`}}
+	if err := c.CompileRegexps(); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		name string
+		file string
+		in   string
+		code int
+		want serveResult
+	}{
+		{
+			name: "conforming",
+			file: "a.go",
+			in:   "// Copyright 2026 X\n// BSD\npackage a\n",
+			code: exitOK,
+			want: serveResult{File: "a.go", Selected: true},
+		},
+		{
+			name: "comment style from the name",
+			file: "tools/a.sh",
+			in:   "# Copyright 2026 X\n# BSD\necho\n",
+			code: exitOK,
+			want: serveResult{File: "tools/a.sh", Selected: true},
+		},
+		{
+			name: "missing",
+			file: "a.go",
+			in:   "// Copyright 2026 X\n// MIT\npackage a\n",
+			code: exitViolations,
+			want: serveResult{File: "a.go", Selected: true, Bucket: string(bucketMissing), Closest: "Licenses[0]"},
+		},
+		{
+			name: "empty",
+			file: "a.go",
+			code: exitViolations,
+			want: serveResult{File: "a.go", Selected: true, Bucket: string(bucketMissing), Closest: "Licenses[0]"},
+		},
+		{
+			name: "not selected",
+			file: "a.txt",
+			in:   "hello\n",
+			code: exitOK,
+			want: serveResult{File: "a.txt"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			if code := checkReader(strings.NewReader(tt.in), &b, tt.file, c, Options{}); code != tt.code {
+				t.Errorf("checkReader() = %d, want %d", code, tt.code)
+			}
+			var got serveResult
+			if err := json.Unmarshal(b.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			detail := got.Detail
+			got.Detail = ""
+			if got != tt.want {
+				t.Errorf("checkReader() wrote %+v, want %+v", got, tt.want)
+			}
+			if (detail != "") != (tt.want.Bucket == string(bucketMissing)) {
+				t.Errorf("checkReader() detail = %q", detail)
+			}
+		})
+	}
+}
@@ -0,0 +1,416 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"bytes"
+	"path"
+	"strings"
+)
+
+// commentStyle describes the comment syntax of a family of languages.
+type commentStyle struct {
+	// line starts a comment which runs to the end of the line, e.g. "//".
+	line string
+	// blockStart and blockEnd delimit a block comment, e.g. "/*" and "*/".
+	blockStart, blockEnd string
+	// blockLine prefixes the lines within block comments written by
+	// comment, e.g. " * ".
+	blockLine string
+}
+
+// cStyle is the comment syntax shared by Go, C and friends.
+var cStyle = commentStyle{line: "//", blockStart: "/*", blockEnd: "*/", blockLine: " * "}
+
+// markupStyle is the comment syntax of HTML, XML, SVG and Markdown.
+var markupStyle = commentStyle{blockStart: "<!--", blockEnd: "-->"}
+
+// hashStyle is the comment syntax of shell, Python, Makefiles and most
+// configuration languages.
+var hashStyle = commentStyle{line: "#"}
+
+// commentStyles maps file extensions to their comment syntax.
+var commentStyles = map[string]commentStyle{
+	".go":    cStyle,
+	".c":     cStyle,
+	".h":     cStyle,
+	".cc":    cStyle,
+	".cpp":   cStyle,
+	".java":  cStyle,
+	".js":    cStyle,
+	".css":   cStyle,
+	".rs":    cStyle,
+	".proto": cStyle,
+	".sh":    hashStyle,
+	".bash":  hashStyle,
+	".py":    hashStyle,
+	".pl":    hashStyle,
+	".rb":    hashStyle,
+	".yml":   hashStyle,
+	".yaml":  hashStyle,
+	".toml":  hashStyle,
+	".mk":    hashStyle,
+	".html":  markupStyle,
+	".htm":   markupStyle,
+	".xhtml": markupStyle,
+	".xml":   markupStyle,
+	".svg":   markupStyle,
+	".md":    markupStyle,
+}
+
+// sniffedStyles maps the extensions of languages whose comment syntax
+// varies between files to a function picking it from their contents.
+var sniffedStyles = map[string]func([]byte) commentStyle{
+	".s": asmStyle,
+	".S": asmStyle,
+}
+
+// asmStyle returns the comment syntax of assembly contents. The Go and GNU
+// assemblers take // and /* */ comments, but some assemblers only take #,
+// which is recognized when it starts the first line, unless the line is a
+// preprocessor directive such as #include.
+func asmStyle(contents []byte) commentStyle {
+	line := bytes.TrimLeft(contents, " \t\r\n")
+	if i := bytes.IndexByte(line, '\n'); i >= 0 {
+		line = line[:i]
+	}
+	if !bytes.HasPrefix(line, []byte("#")) {
+		return cStyle
+	}
+	if rest := line[1:]; len(rest) == 0 || rest[0] == ' ' || rest[0] == '\t' || rest[0] == '#' {
+		return hashStyle
+	}
+	return cStyle
+}
+
+// interpreterStyles maps the interpreters named in shebang lines to the
+// comment syntax of their language.
+var interpreterStyles = map[string]commentStyle{
+	"sh":     hashStyle,
+	"bash":   hashStyle,
+	"dash":   hashStyle,
+	"ash":    hashStyle,
+	"ksh":    hashStyle,
+	"zsh":    hashStyle,
+	"fish":   hashStyle,
+	"python": hashStyle,
+	"perl":   hashStyle,
+	"ruby":   hashStyle,
+	"awk":    hashStyle,
+	"gawk":   hashStyle,
+	"tclsh":  hashStyle,
+	"node":   cStyle,
+	"nodejs": cStyle,
+	"deno":   cStyle,
+}
+
+// styleFor returns the comment syntax of file and whether it is known. Files
+// without an extension are recognized by the interpreter their shebang line
+// names, and assembly files by the comments they start with.
+func styleFor(file string, contents []byte) (commentStyle, bool) {
+	if path.Base(file) == "Makefile" {
+		return hashStyle, true
+	}
+	if sniff, ok := sniffedStyles[path.Ext(file)]; ok {
+		return sniff(contents), true
+	}
+	if ext := path.Ext(file); ext != "" {
+		s, ok := commentStyles[ext]
+		return s, ok
+	}
+	s, ok := interpreterStyles[interpreter(contents)]
+	return s, ok
+}
+
+// interpreter returns the name of the interpreter named by the shebang line
+// of contents, without a version, e.g. "python" for "#!/usr/bin/env
+// python3". It returns "" if there is no shebang.
+func interpreter(contents []byte) string {
+	if !bytes.HasPrefix(contents, []byte("#!")) {
+		return ""
+	}
+	line := contents[2:]
+	if i := bytes.IndexByte(line, '\n'); i >= 0 {
+		line = line[:i]
+	}
+	fields := strings.Fields(string(line))
+	if len(fields) == 0 {
+		return ""
+	}
+	name := path.Base(fields[0])
+	if name == "env" {
+		// Skip the options of env, as in "env -S python3 -u".
+		name = ""
+		for _, f := range fields[1:] {
+			if !strings.HasPrefix(f, "-") {
+				name = path.Base(f)
+				break
+			}
+		}
+	}
+	return strings.TrimRight(name, "0123456789.")
+}
+
+// comment turns lines of text into a comment in style s.
+func (s commentStyle) comment(lines []string) []byte {
+	var b bytes.Buffer
+	if s.line == "" {
+		b.WriteString(s.blockStart + "\n")
+		for _, l := range lines {
+			b.WriteString(strings.TrimRight(s.blockLine+l, " ") + "\n")
+		}
+		// Align the end with the line prefix, as in " */".
+		b.WriteString(s.blockLine[:len(s.blockLine)-len(strings.TrimLeft(s.blockLine, " "))] + s.blockEnd + "\n")
+		return b.Bytes()
+	}
+	for _, l := range lines {
+		b.WriteString(strings.TrimRight(s.line+" "+l, " ") + "\n")
+	}
+	return b.Bytes()
+}
+
+// bannerExts are the extensions of files which may be minified, and whose
+// license is then found in a leading banner comment.
+var bannerExts = map[string]bool{
+	".js":  true,
+	".css": true,
+}
+
+// leadingBanner returns the "/*! ... */" comment at the top of contents, or
+// nil if there is none. By convention minifiers preserve such comments.
+func leadingBanner(contents []byte) []byte {
+	b := bytes.TrimLeft(contents, " \t\r\n")
+	if !bytes.HasPrefix(b, []byte("/*!")) {
+		return nil
+	}
+	end := bytes.Index(b, []byte("*/"))
+	if end < 0 {
+		return nil
+	}
+	return b[:end+len("*/")]
+}
+
+// leadingMarkupComment returns the first "<!-- ... -->" comment of markup
+// contents, or nil if anything but an XML declaration, processing
+// instructions or a doctype precede it.
+func leadingMarkupComment(contents []byte) []byte {
+	b := contents[markupProlog(contents):]
+	b = bytes.TrimLeft(b, " \t\r\n")
+	if !bytes.HasPrefix(b, []byte(markupStyle.blockStart)) {
+		return nil
+	}
+	end := bytes.Index(b, []byte(markupStyle.blockEnd))
+	if end < 0 {
+		return nil
+	}
+	return b[:end+len(markupStyle.blockEnd)]
+}
+
+// markupProlog returns the length of the XML declaration, processing
+// instructions and doctype at the top of markup contents, which must stay
+// ahead of any comment.
+func markupProlog(contents []byte) int {
+	off := 0
+	if bytes.HasPrefix(contents, []byte("\xef\xbb\xbf")) {
+		off = 3
+	}
+	for {
+		b := bytes.TrimLeft(contents[off:], " \t\r\n")
+		if (!bytes.HasPrefix(b, []byte("<?")) && !bytes.HasPrefix(b, []byte("<!"))) || bytes.HasPrefix(b, []byte(markupStyle.blockStart)) {
+			return off
+		}
+		end := bytes.IndexByte(b, '>')
+		if end < 0 {
+			return off
+		}
+		off = len(contents) - len(b) + end + 1
+	}
+}
+
+// stripHeader returns the header region of contents with the comment
+// markers removed, one line of text per line of comment.
+//
+// The header region is the run of comment and blank lines at the top of the
+// file; it ends at the first line of code. Line and block comments may be
+// mixed freely within it, so that a single plain-text license matches no
+// matter how the comment was authored.
+func (s commentStyle) stripHeader(contents []byte) []byte {
+	if bytes.HasPrefix(contents, []byte("#!")) {
+		// The shebang line precedes the header.
+		if i := bytes.IndexByte(contents, '\n'); i >= 0 {
+			contents = contents[i+1:]
+		} else {
+			contents = nil
+		}
+	}
+	var out [][]byte
+	inBlock := false
+	for len(contents) > 0 {
+		var line []byte
+		if i := bytes.IndexByte(contents, '\n'); i >= 0 {
+			line, contents = contents[:i], contents[i+1:]
+		} else {
+			line, contents = contents, nil
+		}
+		text, ok := s.stripLine(bytes.TrimSpace(line), &inBlock)
+		if !ok {
+			break
+		}
+		if text != nil {
+			out = append(out, text)
+		}
+	}
+	// Drop the blank lines between the header and the code.
+	for len(out) > 0 && len(out[len(out)-1]) == 0 {
+		out = out[:len(out)-1]
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return append(bytes.Join(out, []byte("\n")), '\n')
+}
+
+// stripLine removes the comment markers from a single trimmed line of the
+// header region. It returns false when the line is code, which ends the
+// region, and a nil slice for lines holding nothing but a delimiter.
+func (s commentStyle) stripLine(line []byte, inBlock *bool) ([]byte, bool) {
+	delim := false
+	switch {
+	case *inBlock:
+		// Continuation lines of block comments are conventionally
+		// prefixed with " * ".
+		if len(line) > 0 && line[0] == '*' && !bytes.HasPrefix(line, []byte(s.blockEnd)) {
+			line = line[1:]
+		}
+	case len(line) == 0:
+		return line, true
+	case s.line != "" && bytes.HasPrefix(line, []byte(s.line)):
+		return bytes.TrimRight(trimOneSpace(line[len(s.line):]), " \t"), true
+	case s.blockStart != "" && bytes.HasPrefix(line, []byte(s.blockStart)):
+		line = line[len(s.blockStart):]
+		// Banner comments like "/**" and "/*!" carry an extra marker.
+		if len(line) > 0 && (line[0] == '*' || line[0] == '!') {
+			line = line[1:]
+		}
+		*inBlock, delim = true, true
+	default:
+		return nil, false
+	}
+	if i := bytes.Index(line, []byte(s.blockEnd)); s.blockEnd != "" && i >= 0 {
+		line = line[:i]
+		*inBlock, delim = false, true
+	}
+	line = bytes.TrimRight(trimOneSpace(line), " \t")
+	if len(line) == 0 && delim {
+		return nil, true
+	}
+	return line, true
+}
+
+// headerEnd returns the offset just past the first comment block of
+// contents, skipping a leading shebang line. It returns false if contents do
+// not start with a comment.
+func (s commentStyle) headerEnd(contents []byte) (int, bool) {
+	off := 0
+	if bytes.HasPrefix(contents, []byte("#!")) {
+		i := bytes.IndexByte(contents, '\n')
+		if i < 0 {
+			return 0, false
+		}
+		off = i + 1
+	}
+	start := off
+	inBlock := false
+	for off < len(contents) {
+		end := bytes.IndexByte(contents[off:], '\n')
+		if end < 0 {
+			end = len(contents)
+		} else {
+			end += off + 1
+		}
+		line := bytes.TrimSpace(contents[off:end])
+		if len(line) == 0 && !inBlock {
+			break
+		}
+		if _, ok := s.stripLine(line, &inBlock); !ok {
+			break
+		}
+		off = end
+	}
+	return off, off > start
+}
+
+// unwrap joins the soft-wrapped lines at the top of text: every line which
+// does not end a sentence is joined to the following one. Only lines
+// starting with marker are joined, dropping the marker of the continuation
+// line; the first line without it ends the region. A leading shebang line is
+// kept as it is. An empty marker unwraps
+// all of text. Blank lines separate paragraphs and are kept.
+func unwrap(text []byte, marker string) []byte {
+	newline := bytes.HasSuffix(text, []byte("\n"))
+	var out [][]byte
+	if bytes.HasPrefix(text, []byte("#!")) {
+		// Keep the shebang line as it is.
+		i := bytes.IndexByte(text, '\n')
+		if i < 0 {
+			return text
+		}
+		out, text = append(out, text[:i]), text[i+1:]
+	}
+	join := false
+	for len(text) > 0 {
+		var line []byte
+		if i := bytes.IndexByte(text, '\n'); i >= 0 {
+			line, text = text[:i], text[i+1:]
+		} else {
+			line, text = text, nil
+		}
+		trimmed := bytes.TrimSpace(line)
+		if !bytes.HasPrefix(trimmed, []byte(marker)) {
+			out = append(out, line)
+			break
+		}
+		body := bytes.TrimSpace(trimmed[len(marker):])
+		switch {
+		case len(body) == 0:
+			out = append(out, line)
+			join = false
+			continue
+		case join:
+			prev := out[len(out)-1]
+			out[len(out)-1] = append(append(append([]byte(nil), prev...), ' '), body...)
+		default:
+			out = append(out, line)
+		}
+		join = !bytes.ContainsAny(body[len(body)-1:], ".!?:;")
+	}
+	b := bytes.Join(out, []byte("\n"))
+	if len(text) > 0 || newline {
+		b = append(append(b, '\n'), text...)
+	}
+	return b
+}
+
+// blankLines counts the blank lines at the start of b.
+func blankLines(b []byte) int {
+	n := 0
+	for {
+		i := bytes.IndexByte(b, '\n')
+		if i < 0 || len(bytes.TrimSpace(b[:i])) > 0 {
+			return n
+		}
+		n++
+		b = b[i+1:]
+	}
+}
+
+// trimOneSpace removes a single leading space, which conventionally
+// separates a comment marker from the comment text.
+func trimOneSpace(b []byte) []byte {
+	if len(b) > 0 && b[0] == ' ' {
+		return b[1:]
+	}
+	return b
+}
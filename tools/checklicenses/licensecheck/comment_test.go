@@ -0,0 +1,235 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStripHeader(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "line comments",
+			in:   "// a\n//\n// b\n\npackage x\n",
+			want: "a\n\nb\n",
+		},
+		{
+			name: "block comment",
+			in:   "/*\n * a\n *\n * b\n */\npackage x\n",
+			want: "a\n\nb\n",
+		},
+		{
+			name: "single line block",
+			in:   "/* a */\n// b\npackage x\n",
+			want: "a\nb\n",
+		},
+		{
+			name: "banner",
+			in:   "/*! a\n   b */\nvar x\n",
+			want: "a\nb\n",
+		},
+		{
+			name: "code first",
+			in:   "package x\n// a\n",
+			want: "",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(cStyle.stripHeader([]byte(tt.in))); got != tt.want {
+				t.Errorf("stripHeader(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStyleFor(t *testing.T) {
+	for _, tt := range []struct {
+		file     string
+		contents string
+		want     commentStyle
+		ok       bool
+	}{
+		{file: "a.go", want: cStyle, ok: true},
+		{file: "Makefile", want: hashStyle, ok: true},
+		{file: "tools/build", contents: "#!/bin/sh\n", want: hashStyle, ok: true},
+		{file: "tools/gen", contents: "#!/usr/bin/env python3\n", want: hashStyle, ok: true},
+		{file: "tools/run", contents: "#!/usr/bin/env -S python3.11 -u\n", want: hashStyle, ok: true},
+		{file: "tools/serve", contents: "#!/usr/bin/node\n", want: cStyle, ok: true},
+		{file: "tools/data", contents: "hello\n"},
+		{file: "a.bin", contents: "#!/bin/sh\n"},
+		{file: "a_amd64.s", contents: "// Copyright\n", want: cStyle, ok: true},
+		{file: "a_amd64.S", contents: "/*\n * Copyright\n */\n", want: cStyle, ok: true},
+		{file: "a_amd64.s", contents: "\n# Copyright\n", want: hashStyle, ok: true},
+		{file: "a_amd64.s", contents: "#include \"textflag.h\"\n", want: cStyle, ok: true},
+	} {
+		got, ok := styleFor(tt.file, []byte(tt.contents))
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("styleFor(%s, %q) = %+v, %t, want %+v, %t", tt.file, tt.contents, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestShebangHeader(t *testing.T) {
+	c := &Config{Licenses: []License{{Lines: []string{"^Copyright 2026 X\nBSD"}}}, StripComments: true}
+	if err := c.CompileRegexps(); err != nil {
+		t.Fatal(err)
+	}
+	if in := "#!/bin/sh\n# Copyright 2026 X\n# BSD\n\necho\n"; !c.match("tools/build", []byte(in)) {
+		t.Errorf("match(%q) = false, want true", in)
+	}
+}
+
+func TestMixedCommentStyles(t *testing.T) {
+	c, err := loadConfig("testdata/comments/config.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for file, want := range map[string]bool{
+		"line.go":             true,
+		"block.go":            true,
+		"mixed.go":            true,
+		"mixed_block_body.go": true,
+		"after_code.go":       false,
+	} {
+		contents, err := os.ReadFile(filepath.Join("testdata/comments", file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := c.match(file, contents); got != want {
+			t.Errorf("match(%s) = %t, want %t", file, got, want)
+		}
+	}
+}
+
+func TestBanner(t *testing.T) {
+	c, err := loadConfig("testdata/comments/config.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for file, want := range map[string]bool{
+		"bundle.min.js":     true,
+		"plain.js":          true,
+		"nobanner.min.js":   false,
+		"truncated.min.css": false,
+	} {
+		contents, err := os.ReadFile(filepath.Join("testdata/banner", file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := c.match(file, contents); got != want {
+			t.Errorf("match(%s) = %t, want %t", file, got, want)
+		}
+	}
+}
+
+func TestMarkup(t *testing.T) {
+	c, err := loadConfig("testdata/comments/config.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for file, want := range map[string]bool{
+		"doc.html":      true,
+		"image.svg":     true,
+		"late.html":     false,
+		"nocomment.xml": false,
+	} {
+		contents, err := os.ReadFile(filepath.Join("testdata/markup", file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := c.match(file, contents); got != want {
+			t.Errorf("match(%s) = %t, want %t", file, got, want)
+		}
+	}
+}
+
+func TestAssembly(t *testing.T) {
+	c, err := loadConfig("testdata/asm/config.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for file, want := range map[string]bool{
+		"plan9_amd64.s": true,
+		"gnu_amd64.S":   true,
+		"hash_amd64.s":  true,
+		"late_amd64.s":  false,
+	} {
+		contents, err := os.ReadFile(filepath.Join("testdata/asm", file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := c.match(file, contents); got != want {
+			t.Errorf("match(%s) = %t, want %t", file, got, want)
+		}
+	}
+}
+
+func TestUnwrap(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		in     string
+		marker string
+		want   string
+	}{
+		{
+			name:   "sentences",
+			in:     "// a\n// b.\n// c\n",
+			marker: "//",
+			want:   "// a b.\n// c\n",
+		},
+		{
+			name:   "paragraphs",
+			in:     "// a\n//\n// b\n",
+			marker: "//",
+			want:   "// a\n//\n// b\n",
+		},
+		{
+			name:   "code ends header",
+			in:     "// a\npackage x\n// b\n",
+			marker: "//",
+			want:   "// a\npackage x\n// b\n",
+		},
+		{
+			name: "plain text",
+			in:   "a\nb\n\nc",
+			want: "a b\n\nc",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(unwrap([]byte(tt.in), tt.marker)); got != tt.want {
+				t.Errorf("unwrap(%q, %q) = %q, want %q", tt.in, tt.marker, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnwrapHeaders(t *testing.T) {
+	c, err := loadConfig("testdata/unwrap/config.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, unwrapHeaders := range []bool{true, false} {
+		c.UnwrapHeaders = unwrapHeaders
+		for file, want := range map[string]bool{
+			"unwrapped.go":  true,
+			"wrapped.go":    true,
+			"paragraphs.go": false,
+		} {
+			contents, err := os.ReadFile(filepath.Join("testdata/unwrap", file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := c.match(file, contents); got != (want && unwrapHeaders) {
+				t.Errorf("UnwrapHeaders %t: match(%s) = %t, want %t", unwrapHeaders, file, got, want && unwrapHeaders)
+			}
+		}
+	}
+}
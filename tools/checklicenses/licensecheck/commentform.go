@@ -0,0 +1,97 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Forms of comments in CommentForms.
+const (
+	formLine  = "line"
+	formBlock = "block"
+)
+
+// headerForm returns the form of the comment at the top of contents, after
+// any shebang line: formLine, formBlock, or "" if there is no comment.
+func (s commentStyle) headerForm(contents []byte) string {
+	if bytes.HasPrefix(contents, []byte("#!")) {
+		i := bytes.IndexByte(contents, '\n')
+		if i < 0 {
+			return ""
+		}
+		contents = contents[i+1:]
+	}
+	contents = bytes.TrimLeft(contents, " \t")
+	switch {
+	case s.line != "" && bytes.HasPrefix(contents, []byte(s.line)):
+		return formLine
+	case s.blockStart != "" && bytes.HasPrefix(contents, []byte(s.blockStart)):
+		return formBlock
+	}
+	return ""
+}
+
+// checkCommentForms returns an error if CommentForms names an unknown form,
+// or one the comment syntax of the extension lacks.
+func (c *Config) checkCommentForms() error {
+	for ext, form := range c.CommentForms {
+		s, ok := commentStyles[ext]
+		switch {
+		case !ok:
+			return fmt.Errorf("CommentForms: unknown extension %q", ext)
+		case form == formLine && s.line == "", form == formBlock && s.blockStart == "":
+			return fmt.Errorf("CommentForms: %s files have no %s comments", ext, form)
+		case form != formLine && form != formBlock:
+			return fmt.Errorf("CommentForms: unknown form %q for %s, want %s or %s", form, ext, formLine, formBlock)
+		}
+	}
+	return nil
+}
+
+// wrongCommentForm reports whether the header of file is written in
+// another form than CommentForms requires.
+func (c *Config) wrongCommentForm(file string, contents []byte) bool {
+	want, ok := c.CommentForms[path.Ext(file)]
+	if !ok {
+		return false
+	}
+	style, ok := c.styleFor(file, contents)
+	if !ok {
+		return false
+	}
+	got := style.headerForm(contents)
+	return got != "" && got != want
+}
+
+// fixCommentForm rewrites the first comment of contents, which holds the
+// header, from line comments to a block comment or the other way round.
+func (c *Config) fixCommentForm(file string, contents []byte) ([]byte, error) {
+	style, ok := c.styleFor(file, contents)
+	if !ok {
+		return nil, fmt.Errorf("cannot fix %s: unknown comment syntax", file)
+	}
+	end, ok := style.headerEnd(contents)
+	if !ok {
+		return nil, fmt.Errorf("cannot fix %s: no header found", file)
+	}
+	to := style
+	if style.headerForm(contents) == formLine {
+		// comment writes block comments for styles without line
+		// comments.
+		to.line = ""
+	}
+	var b bytes.Buffer
+	if bytes.HasPrefix(contents, []byte("#!")) {
+		b.Write(contents[:bytes.IndexByte(contents, '\n')+1])
+	}
+	text := strings.TrimSuffix(string(style.stripHeader(contents[:end])), "\n")
+	b.Write(to.comment(strings.Split(text, "\n")))
+	b.Write(contents[end:])
+	return b.Bytes(), nil
+}
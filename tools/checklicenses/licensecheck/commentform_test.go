@@ -0,0 +1,64 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import "testing"
+
+func TestCommentForms(t *testing.T) {
+	c := &Config{
+		Licenses:     []License{{Lines: []string{"Copyright X"}}},
+		CommentForms: map[string]string{".go": formLine, ".c": formBlock},
+	}
+	if err := c.CompileRegexps(); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		file string
+		in   string
+		want bucket
+		fix  string
+	}{
+		{file: "a.go", in: "// Copyright X\n// BSD\n\npackage a\n"},
+		{
+			file: "a.go",
+			in:   "/*\n * Copyright X\n * BSD\n */\n\npackage a\n",
+			want: bucketCommentStyle,
+			fix:  "// Copyright X\n// BSD\n\npackage a\n",
+		},
+		{file: "a.c", in: "/*\n * Copyright X\n */\nint x;\n"},
+		{
+			file: "a.c",
+			in:   "// Copyright X\n// BSD\n\nint x;\n",
+			want: bucketCommentStyle,
+			fix:  "/*\n * Copyright X\n * BSD\n */\n\nint x;\n",
+		},
+		{file: "a.h", in: "// Copyright X\nint x;\n"},
+	} {
+		if got := c.checkContents(tt.file, []byte(tt.in)); got != tt.want {
+			t.Errorf("checkContents(%s, %q) = %q, want %q", tt.file, tt.in, got, tt.want)
+		}
+		if tt.want == "" {
+			continue
+		}
+		fixed, err := c.fixCommentForm(tt.file, []byte(tt.in))
+		if err != nil || string(fixed) != tt.fix {
+			t.Errorf("fixCommentForm(%s, %q) = %q, %v, want %q", tt.file, tt.in, fixed, err, tt.fix)
+		}
+		if got := c.checkContents(tt.file, fixed); got != "" {
+			t.Errorf("checkContents(%s, %q) after fixing = %q, want no violation", tt.file, fixed, got)
+		}
+	}
+
+	for _, forms := range []map[string]string{
+		{".sh": formBlock},
+		{".go": "doc"},
+		{".unknown": formLine},
+	} {
+		c := &Config{CommentForms: forms}
+		if err := c.CompileRegexps(); err == nil {
+			t.Errorf("CompileRegexps() with CommentForms %v succeeded, want error", forms)
+		}
+	}
+}
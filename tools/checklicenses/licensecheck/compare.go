@@ -0,0 +1,119 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+)
+
+// Kinds of change between the results of two configurations.
+const (
+	newlyFailing  = "newly-failing"
+	newlyPassing  = "newly-passing"
+	changedBucket = "changed-bucket"
+)
+
+// change is a file whose result differs between two configurations.
+type change struct {
+	file string
+	kind string
+	// before and after are the results under each configuration: a
+	// bucket, "pass", or "unchecked" for files the configuration does not
+	// select.
+	before, after string
+}
+
+// result describes the outcome of checking a file, for a change.
+func result(checked bool, b bucket) string {
+	switch {
+	case !checked:
+		return "unchecked"
+	case b == "":
+		return "pass"
+	}
+	return string(b)
+}
+
+// compareCandidates checks the files selected by either of two
+// configurations against both, reading each once, and returns the files
+// whose pass or fail status or bucket differ, sorted by name.
+func compareCandidates(ctx context.Context, before, after []candidate) ([]change, error) {
+	olds := make(map[string]candidate, len(before))
+	for _, cd := range before {
+		olds[cd.file] = cd
+	}
+	news := make(map[string]candidate, len(after))
+	var files []string
+	for _, cd := range after {
+		news[cd.file] = cd
+		if _, ok := olds[cd.file]; !ok {
+			files = append(files, cd.file)
+		}
+	}
+	for _, cd := range before {
+		files = append(files, cd.file)
+	}
+	sort.Strings(files)
+
+	var changes []change
+	for _, file := range files {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		o, inOld := olds[file]
+		n, inNew := news[file]
+		cd := o
+		if !inOld {
+			cd = n
+		}
+		name, contents, err := cd.read()
+		if err != nil {
+			return nil, err
+		}
+		if contents == nil {
+			continue
+		}
+		var ob, nb bucket
+		if inOld {
+			v := o.classify(name, contents)
+			ob, inOld = v.bucket, v.skipped == ""
+		}
+		if inNew {
+			v := n.classify(name, contents)
+			nb, inNew = v.bucket, v.skipped == ""
+		}
+		c := change{file: file, before: result(inOld, ob), after: result(inNew, nb)}
+		switch {
+		case nb != "" && ob == "":
+			c.kind = newlyFailing
+		case nb == "" && ob != "":
+			c.kind = newlyPassing
+		case nb != ob:
+			c.kind = changedBucket
+		default:
+			continue
+		}
+		changes = append(changes, c)
+	}
+	return changes, nil
+}
+
+// printChanges prints the changes, and how many there are of each kind.
+// display formats the file names.
+func printChanges(w io.Writer, changes []change, display func(string) string) {
+	count := map[string]int{}
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "CHANGE\tFILE\tBEFORE\tAFTER\n")
+	for _, c := range changes {
+		count[c.kind]++
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", c.kind, display(c.file), c.before, c.after)
+	}
+	tw.Flush()
+	fmt.Fprintf(w, "%d %s, %d %s, %d %s\n", count[newlyFailing], newlyFailing, count[newlyPassing], newlyPassing, count[changedBucket], changedBucket)
+}
@@ -0,0 +1,70 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCompareCandidates(t *testing.T) {
+	dir := t.TempDir()
+	for name, contents := range map[string]string{
+		"bsd.go":       "// BSD\npackage a\n",
+		"apache.go":    "// Apache\npackage a\n",
+		"none.go":      "package a\n",
+		"gpl.go":       "// GPL\npackage a\n",
+		"mit.go":       "// MIT\npackage a\n",
+		"script.sh":    "echo\n",
+		"unchanged.go": "// BSD\npackage a\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	before := &Config{Licenses: []License{{Lines: []string{"// BSD"}}, {Lines: []string{"// Apache"}}}}
+	after := &Config{
+		Licenses:  []License{{Lines: []string{"// BSD"}}, {Lines: []string{"// GPL"}}},
+		Forbidden: []License{{Lines: []string{"// Apache"}}, {Lines: []string{"// MIT"}}},
+	}
+	for _, c := range []*Config{before, after} {
+		if err := c.CompileRegexps(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	candidates := func(c *Config, names ...string) []candidate {
+		var cds []candidate
+		for _, n := range names {
+			cds = append(cds, candidate{file: filepath.Join(dir, n), config: c})
+		}
+		return cds
+	}
+	changes, err := compareCandidates(context.Background(),
+		candidates(before, "bsd.go", "apache.go", "none.go", "gpl.go", "mit.go", "unchanged.go"),
+		candidates(after, "bsd.go", "apache.go", "gpl.go", "mit.go", "script.sh", "unchanged.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	printChanges(&b, changes, filepath.Base)
+	want := `CHANGE          FILE       BEFORE     AFTER
+newly-failing   apache.go  pass       forbidden
+newly-passing   gpl.go     missing    pass
+changed-bucket  mit.go     missing    forbidden
+newly-passing   none.go    missing    unchecked
+newly-failing   script.sh  unchecked  missing
+2 newly-failing, 2 newly-passing, 1 changed-bucket
+`
+	if got := b.String(); got != want {
+		t.Errorf("printChanges() =\n%s\nwant\n%s", got, want)
+	}
+	if want := (change{file: filepath.Join(dir, "mit.go"), kind: changedBucket, before: "missing", after: "forbidden"}); len(changes) != 5 || !reflect.DeepEqual(changes[2], want) {
+		t.Errorf("compareCandidates() = %+v, want [2] %+v", changes, want)
+	}
+}
@@ -0,0 +1,784 @@
+// Copyright 2017-2018 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+type rule struct {
+	*regexp.Regexp
+	invert bool
+}
+
+func accept(s string) (rule, error) {
+	re, err := regexp.Compile("^" + s + "$")
+	return rule{re, false}, err
+}
+
+func reject(s string) (rule, error) {
+	re, err := regexp.Compile("^" + s + "$")
+	return rule{re, true}, err
+}
+
+// License is an acceptable license header.
+//
+// In JSON it is either an array of strings, one string per line without the
+// trailing \n, or an object. The object form may name a File holding the
+// license text instead of listing it inline.
+type License struct {
+	// Name optionally identifies the license in messages.
+	Name string `json:",omitempty"`
+	// Version optionally tells revisions of the license apart, like the
+	// old and new headers during a relicensing. -license-report counts
+	// the files carrying each version.
+	Version string `json:",omitempty"`
+	// Lines is the license regexp, one string per line.
+	Lines []string `json:",omitempty"`
+	// File is a file holding the license regexp. Relative paths are
+	// relative to the directory of the configuration file.
+	File string `json:",omitempty"`
+	// SPDX is the SPDX license expression of the license, e.g.
+	// "BSD-3-Clause". Files whose text matches the license but which
+	// are tagged with another SPDX-License-Identifier are reported.
+	SPDX string `json:",omitempty"`
+	// Require is text which the header of files carrying the license
+	// must also contain, like "see the NOTICE file". Files lacking it
+	// are reported. Whitespace in it matches any whitespace.
+	Require string `json:",omitempty"`
+	// MinLines is the number of lines, not counting blank ones, which
+	// the header of files carrying the license must at least span, to
+	// catch truncated headers passing a loose regexp. 0 means any.
+	MinLines int `json:",omitempty"`
+	// MustNotContain are regexps which the header of files carrying the
+	// license must not match, like "non-commercial", to catch restricted
+	// variants of a permissive license. Files matching one are reported
+	// as forbidden, with the phrase found.
+	MustNotContain []string `json:",omitempty"`
+	mustNotContain []*regexp.Regexp
+	// lineRegexps are the Lines compiled one by one, for -show-mismatch.
+	lineRegexps []*regexp.Regexp
+	// EnforceAfter, for Forbidden licenses and the licenses of DistFiles,
+	// is the date, as YYYY-MM-DD, from which their violations fail the
+	// run. Until then they are reported as warnings.
+	EnforceAfter string `json:",omitempty"`
+	enforceAfter time.Time
+}
+
+// id returns the Name of the license, or else its position in the
+// configuration, e.g. "Forbidden[1]".
+func (l *License) id(what string, i int) string {
+	if l.Name != "" {
+		return l.Name
+	}
+	return fmt.Sprintf("%s[%d]", what, i)
+}
+
+// literalYears matches a year or a list or range of years, like "2017-2018".
+var literalYears = regexp.MustCompile(`\b[0-9]{4}(?:[ \t]*[-,–][ \t]*[0-9]{4})*\b`)
+
+// anyYears is the regexp which IgnoreYears substitutes for literal years.
+const anyYears = `[0-9]{4}(?:[ \t]*[-,–][ \t]*[0-9]{4})*`
+
+// compile compiles the license regexp. what and i locate the license in the
+// configuration for error messages. ignoreYears replaces the years written in
+// the license with a pattern matching any years.
+func (l *License) compile(what string, i int, ignoreYears bool) (*regexp.Regexp, error) {
+	pattern := strings.Join(l.Lines, "\n")
+	if ignoreYears {
+		pattern = literalYears.ReplaceAllLiteralString(pattern, anyYears)
+	}
+	if err := l.compileMustNotContain(what, i); err != nil {
+		return nil, err
+	}
+	if l.MinLines < 0 {
+		return nil, fmt.Errorf("%s[%d]: MinLines cannot be negative", what, i)
+	}
+	l.compileLines(ignoreYears)
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		where := fmt.Sprintf("%s[%d]", what, i)
+		if l.Name != "" {
+			where += fmt.Sprintf(" (%s)", l.Name)
+		}
+		if l.File != "" {
+			where += fmt.Sprintf(" from %s", l.File)
+		}
+		return nil, fmt.Errorf("%s: invalid regexp %q: %v", where, pattern, err)
+	}
+	return re, nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (l *License) UnmarshalJSON(b []byte) error {
+	var lines []string
+	if err := json.Unmarshal(b, &lines); err == nil {
+		*l = License{Lines: lines}
+		return nil
+	}
+	// Avoid recursing into this method.
+	type license License
+	return json.Unmarshal(b, (*license)(l))
+}
+
+// stringList is a list of strings which may be written in JSON as a single
+// string.
+type stringList []string
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (l *stringList) UnmarshalJSON(b []byte) error {
+	var s *string
+	if err := json.Unmarshal(b, &s); err == nil {
+		if s != nil {
+			*l = stringList{*s}
+		}
+		return nil
+	}
+	return json.Unmarshal(b, (*[]string)(l))
+}
+
+// DistFile is a license distribution file required in the repository.
+type DistFile struct {
+	// Name is a glob matching the base name of the file, e.g. "LICENSE*".
+	Name string
+	// License is the license the file must contain.
+	License License
+	license *regexp.Regexp
+	// PerModule requires the file in every directory holding a go.mod,
+	// rather than only at the top of the repository.
+	PerModule bool
+}
+
+// DirRule requires every directory matching DirGlob to hold at least one of
+// the files named in RequireOneOf.
+type DirRule struct {
+	// DirGlob is a glob matching directories, e.g. "vendor/*/*".
+	DirGlob string
+	// RequireOneOf lists file names of which one must be present.
+	RequireOneOf []string
+	// EnforceAfter is the date, as YYYY-MM-DD, from which violations of
+	// the rule fail the run. Until then they are reported as warnings.
+	EnforceAfter string `json:",omitempty"`
+	enforceAfter time.Time
+}
+
+// Config contains the rules for license checking.
+type Config struct {
+	// Licenses is a list of acceptable license headers.
+	Licenses        []License
+	licensesRegexps []*regexp.Regexp
+	// profile, if not nil, accumulates match statistics per license.
+	profile []licenseProfile
+	// Forbidden is a list of license headers which must not be used.
+	// Files carrying one of them are reported as forbidden even if they
+	// also carry an acceptable license.
+	Forbidden        []License
+	forbiddenRegexps []*regexp.Regexp
+	// StripComments, if set, removes the comment markers from the header
+	// region of each file before matching, so that Licenses written as
+	// plain text match headers using any mix of line and block comments.
+	StripComments bool
+	// LicenseURLs are URLs of licenses which a header may refer to
+	// instead of carrying one of Licenses, as in
+	// "// License: https://example.com/LICENSE". The scheme and a
+	// trailing slash are ignored when comparing them.
+	LicenseURLs []string
+	licenseURLs map[string]bool
+	// IgnoreYears, if set, lets the years written in Licenses, like
+	// "2018" or "2017-2018", match any year or range of years, so that a
+	// license can be copied verbatim from a header without the copyright
+	// year ever causing a failure.
+	IgnoreYears bool
+	// UnwrapHeaders, if set, also matches Licenses against the header
+	// with soft-wrapped lines joined: a line not ending a sentence is
+	// joined to the next with a single space. A license written with its
+	// sentences on single lines then matches however an editor wrapped
+	// them. Unwrapping may merge lines which were meant to be separate,
+	// so such licenses should be written in the joined form.
+	UnwrapHeaders bool
+	// Owner is the expected copyright holder. If set, files whose header
+	// names another holder are reported as wrong-holder violations. The
+	// holder is the "holder" subexpression of the matching license, or
+	// else the text following the year on the first Copyright line.
+	Owner string
+	// FoldHolderCase compares the copyright holder with Owner regardless
+	// of case, and StripHolderAccents regardless of diacritics, so that
+	// "Müller" matches "Muller". By default they must be the same bytes.
+	FoldHolderCase     bool
+	StripHolderAccents bool
+	// MinYear and MaxYear, if set, bound the copyright years a header
+	// may claim, e.g. to reject years before the project was founded.
+	// Files claiming a year outside them are reported as year-range
+	// violations. The years are the "year" subexpression of the matching
+	// license, or else those on the first Copyright line.
+	MinYear int
+	MaxYear int
+	// GitYears reports files whose copyright years do not span their git
+	// history, from the year of their first commit to that of their last,
+	// as stale-year violations, as -git-years does for all files. It is
+	// ignored with -walk, outside of git.
+	GitYears bool `json:",omitempty"`
+	// HeaderLines and HeaderBytes, if set, bound the region at the top of
+	// each file where the license header must appear, to its first lines
+	// and bytes. Headers beyond the region are not matched, so such
+	// files are reported as missing. By default the region is the whole
+	// file.
+	HeaderLines int
+	HeaderBytes int
+	// HeaderEnd tells where the header whose text is extracted for
+	// matching, with StripComments, and for -discover ends:
+	//
+	//	first-code  at the first line of code (the default), so that
+	//	            comments separated by blank lines make one header
+	//	blank-line  at the first blank line, so that a doc comment
+	//	            below the license is not part of it
+	//	lines:N     after the first N lines of the file, or at the
+	//	            first line of code if that comes first
+	//
+	// A shebang line always precedes the header, and counts towards N.
+	// Build constraints like //go:build are comments, so they start
+	// the header: with blank-line, a license below them is cut off.
+	// -fix edits the first comment block whatever HeaderEnd says.
+	HeaderEnd      string
+	headerEndLines int
+	// VolatilePatterns are regexps matching text which varies between
+	// builds, like a version or date stamped into the header of
+	// generated files. Their matches within the first 8 KiB are removed
+	// before matching licenses, along with lines left holding nothing
+	// else, so that the stable license text still matches.
+	VolatilePatterns []string
+	volatile         []*regexp.Regexp
+	// CommentForms maps file extensions to the form of comment their
+	// header must be written in, "line" or "block", e.g. {".go": "line"}.
+	// Files breaking the rule are reported as comment-style violations.
+	CommentForms map[string]string
+	// BlankLineAfterHeader requires the license header to be followed by
+	// exactly one blank line. Files breaking the rule are reported as
+	// formatting violations.
+	BlankLineAfterHeader bool
+	// SPDXMode tells how SPDX-License-Identifier tags license files:
+	//
+	//	""      they do not, only headers matching Licenses do (the default)
+	//	spdx    they do instead of Licenses, which are not matched
+	//	either  either of a tag and a header matching Licenses does, for
+	//	        migrating a tree to SPDX tags incrementally
+	//	both    files need both a header matching Licenses and a tag
+	//
+	// Files whose tag is missing with "both" are reported as spdx-tag
+	// violations.
+	SPDXMode string `json:",omitempty"`
+	// SPDXAllowed, if not empty, lists the SPDX license and exception
+	// ids which tags may name, like "BSD-3-Clause". Files whose tag names
+	// another, in any SPDXMode, are reported as spdx-tag violations.
+	SPDXAllowed []string `json:",omitempty"`
+	// HeaderTemplates maps file extensions, or the names of files
+	// without one like "Makefile", to the header -fix inserts into them,
+	// one string per line, in the syntax of the -template file, e.g.
+	// {".sh": ["Copyright {{year}} {{holder}}"]}. Other files get the
+	// -template header.
+	HeaderTemplates map[string][]string `json:",omitempty"`
+	// GoPkg is the Go package name to check for licenses. It is trimmed
+	// from the paths matched against the rules and printed in the report.
+	// In a repository holding several modules it may be a list, and each
+	// entry may be a glob; the first entry matching a leading part of a
+	// file's path is trimmed from it.
+	GoPkg stringList
+	goPkg []string
+	// Accept is a list of file patterns to include in the license checking.
+	// It is an allowlist: files which neither Accept nor AcceptExtensions
+	// select are never checked, so new kinds of files only enter the
+	// check once a rule names them.
+	Accept []string
+	accept []rule
+	// Reject is a list of file patterns to exclude from the license
+	// checking. It takes precedence over Accept: a file matching both is
+	// not checked.
+	Reject []string
+	reject []rule
+	// RejectContent is a list of regexps matched against the first 8 KiB
+	// of each selected file, like banners of generators which cannot be
+	// told by path. Matching files are skipped before any license check.
+	RejectContent []string
+	rejectContent []*regexp.Regexp
+	// Ignore is a list of patterns in gitignore syntax, as read from an
+	// -ignore-file. Files they match are rejected.
+	Ignore []string
+	ignore []ignoreRule
+	// AcceptExtensions and RejectExtensions are shorthands for Accept and
+	// Reject rules selecting files by extension, e.g. ".go". They are
+	// looked up before the Accept and Reject patterns.
+	AcceptExtensions []string
+	acceptExts       map[string]bool
+	RejectExtensions []string
+	rejectExts       map[string]bool
+	// FrontMatter, if set, checks documents declaring their license in
+	// YAML front matter.
+	FrontMatter *FrontMatter `json:",omitempty"`
+	// DistFiles lists the license distribution files, like LICENSE or
+	// NOTICE, which the repository must ship.
+	DistFiles []DistFile
+	// AssetDirs give directories of assets their own license policy.
+	AssetDirs []AssetDir
+	// style, if not nil, overrides the comment syntax of every file, as
+	// in the configuration derived for an AssetDir.
+	style *commentStyle
+	// Vendor is a list of globs matching directories of third-party code,
+	// relative to GoPkg. Files within them are checked only for carrying
+	// some well-known license rather than one of Licenses.
+	Vendor []string
+	// DepLicenses, if not empty, lists the SPDX ids of the licenses which
+	// the modules the build depends on may carry, like "BSD-3-Clause".
+	// -deps fails if one carries another.
+	DepLicenses []string `json:",omitempty"`
+	// DirRules require files, typically a LICENSE, in directories.
+	DirRules []DirRule
+	// EnforceAfter maps buckets to the date, as YYYY-MM-DD, from which
+	// their violations fail the run, so that new rules can be staged.
+	// Until then they are reported as warnings.
+	EnforceAfter map[bucket]string
+	enforceAfter map[bucket]time.Time
+	// Severity maps buckets to the severity of their violations in
+	// machine-readable reports: "error", "warning" or "note". Unlisted
+	// buckets keep their default severity. It does not affect the exit
+	// code, which -fail-on controls.
+	Severity map[bucket]string
+	// NoLicenseDirs is a list of directories, relative to GoPkg, whose
+	// files are never checked. It is evaluated before Accept and Reject.
+	NoLicenseDirs []string
+}
+
+// loadConfig reads the JSON configuration in file and compiles it.
+func loadConfig(file string) (*Config, error) {
+	if file == "" {
+		return nil, fmt.Errorf("config file name cannot be empty")
+	}
+	buf, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %v", file, err)
+	}
+	var config Config
+	if err := json.Unmarshal(buf, &config); err != nil {
+		return nil, fmt.Errorf("cannot unmarshal JSON from config file %s: %v", file, err)
+	}
+	if err := config.readLicenseFiles(filepath.Dir(file)); err != nil {
+		return nil, err
+	}
+	if err := config.CompileRegexps(); err != nil {
+		return nil, fmt.Errorf("failed to compile regexps from JSON config: %v", err)
+	}
+	return &config, nil
+}
+
+// readLicenseFiles loads the text of the licenses which refer to a File,
+// resolving relative names against dir.
+func (c *Config) readLicenseFiles(dir string) error {
+	for _, l := range c.allLicenses() {
+		if l.File == "" {
+			continue
+		}
+		if len(l.Lines) > 0 {
+			return fmt.Errorf("license has both Lines and File %s", l.File)
+		}
+		name := l.File
+		if !filepath.IsAbs(name) {
+			name = filepath.Join(dir, name)
+		}
+		buf, err := os.ReadFile(name)
+		if err != nil {
+			return fmt.Errorf("failed to read license file: %v", err)
+		}
+		l.Lines = strings.Split(strings.TrimSuffix(string(buf), "\n"), "\n")
+	}
+	return nil
+}
+
+// resolved returns a copy of the configuration with license Files read
+// inline and GoPkg expanded, which behaves the same when loaded from any
+// directory and environment.
+func (c *Config) resolved() *Config {
+	r := *c
+	r.Licenses = inlineLicenses(c.Licenses)
+	r.Forbidden = inlineLicenses(c.Forbidden)
+	r.DistFiles = append([]DistFile(nil), c.DistFiles...)
+	for i := range r.DistFiles {
+		r.DistFiles[i].License.File = ""
+	}
+	r.AssetDirs = append([]AssetDir(nil), c.AssetDirs...)
+	for i := range r.AssetDirs {
+		r.AssetDirs[i].Licenses = inlineLicenses(c.AssetDirs[i].Licenses)
+	}
+	r.GoPkg = append(stringList(nil), c.goPkg...)
+	return &r
+}
+
+// inlineLicenses returns a copy of licenses without their File names. The
+// text of the files has already been read into their Lines.
+func inlineLicenses(licenses []License) []License {
+	l := append([]License(nil), licenses...)
+	for i := range l {
+		l[i].File = ""
+	}
+	return l
+}
+
+// allLicenses returns every license in the configuration.
+func (c *Config) allLicenses() []*License {
+	var l []*License
+	for i := range c.Licenses {
+		l = append(l, &c.Licenses[i])
+	}
+	for i := range c.Forbidden {
+		l = append(l, &c.Forbidden[i])
+	}
+	for i := range c.DistFiles {
+		l = append(l, &c.DistFiles[i].License)
+	}
+	for i := range c.AssetDirs {
+		for j := range c.AssetDirs[i].Licenses {
+			l = append(l, &c.AssetDirs[i].Licenses[j])
+		}
+	}
+	return l
+}
+
+// CompileRegexps compiles the regular expressions coming from the JSON
+// configuration, and returns an error if an invalid regexp is found.
+func (c *Config) CompileRegexps() error {
+	for i := range c.Licenses {
+		re, err := c.Licenses[i].compile("Licenses", i, c.IgnoreYears)
+		if err != nil {
+			return err
+		}
+		c.licensesRegexps = append(c.licensesRegexps, re)
+	}
+	for i := range c.Forbidden {
+		re, err := c.Forbidden[i].compile("Forbidden", i, c.IgnoreYears)
+		if err != nil {
+			return err
+		}
+		c.forbiddenRegexps = append(c.forbiddenRegexps, re)
+	}
+
+	for i := range c.DistFiles {
+		d := &c.DistFiles[i]
+		if _, err := path.Match(d.Name, ""); err != nil {
+			return fmt.Errorf("invalid DistFiles name %q: %v", d.Name, err)
+		}
+		re, err := d.License.compile("DistFiles", i, c.IgnoreYears)
+		if err != nil {
+			return err
+		}
+		d.license = re
+	}
+
+	for i, g := range c.Vendor {
+		if _, err := path.Match(g, ""); err != nil {
+			return fmt.Errorf("Vendor[%d]: invalid glob %q: %v", i, g, err)
+		}
+	}
+
+	for i, r := range c.DirRules {
+		if _, err := path.Match(r.DirGlob, ""); err != nil {
+			return fmt.Errorf("DirRules[%d]: invalid glob %q: %v", i, r.DirGlob, err)
+		}
+	}
+
+	c.goPkg = make([]string, 0, len(c.GoPkg))
+	for i, raw := range c.GoPkg {
+		p, unset := expandEnv(raw)
+		if len(unset) > 0 {
+			if *strictEnv {
+				return fmt.Errorf("GoPkg[%d] %q: unset environment variables %v", i, raw, unset)
+			}
+			log.Printf("warning: GoPkg[%d] %q: unset environment variables %v", i, raw, unset)
+		}
+		if p == "" && raw != "" {
+			return fmt.Errorf("GoPkg[%d] %q expands to the empty string", i, raw)
+		}
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("invalid GoPkg %q: %v", p, err)
+		}
+		c.goPkg = append(c.goPkg, p)
+	}
+
+	for i, d := range c.NoLicenseDirs {
+		c.NoLicenseDirs[i] = path.Clean(d)
+	}
+
+	if err := c.compileRejectContent(); err != nil {
+		return err
+	}
+
+	if err := c.compileFrontMatter(); err != nil {
+		return err
+	}
+
+	if err := c.checkYearRange(); err != nil {
+		return err
+	}
+
+	if err := c.checkHeaderRegion(); err != nil {
+		return err
+	}
+
+	if err := c.compileVolatile(); err != nil {
+		return err
+	}
+
+	if err := c.compileHeaderEnd(); err != nil {
+		return err
+	}
+
+	if err := c.checkSPDXMode(); err != nil {
+		return err
+	}
+
+	for key, lines := range c.HeaderTemplates {
+		if key == "" || len(lines) == 0 {
+			return fmt.Errorf("HeaderTemplates: invalid template %q: %q", key, lines)
+		}
+	}
+
+	if err := c.checkCommentForms(); err != nil {
+		return err
+	}
+
+	if err := c.compileEnforceAfter(); err != nil {
+		return err
+	}
+
+	if err := checkSeverities(c.Severity); err != nil {
+		return fmt.Errorf("Severity: %v", err)
+	}
+
+	if err := c.compileIgnores(); err != nil {
+		return err
+	}
+
+	c.licenseURLs = make(map[string]bool, len(c.LicenseURLs))
+	for _, u := range c.LicenseURLs {
+		c.licenseURLs[normalizeURL(u)] = true
+	}
+
+	c.acceptExts = extensionSet(c.AcceptExtensions)
+	c.rejectExts = extensionSet(c.RejectExtensions)
+
+	c.accept = make([]rule, 0, len(c.Accept))
+	for i, s := range c.Accept {
+		r, err := accept(s)
+		if err != nil {
+			return fmt.Errorf("Accept[%d]: invalid regexp %q: %v", i, s, err)
+		}
+		c.accept = append(c.accept, r)
+	}
+
+	c.reject = make([]rule, 0, len(c.Reject))
+	for i, s := range c.Reject {
+		r, err := reject(s)
+		if err != nil {
+			return fmt.Errorf("Reject[%d]: invalid regexp %q: %v", i, s, err)
+		}
+		c.reject = append(c.reject, r)
+	}
+
+	// Derive the asset configurations last, from the compiled rules.
+	if err := c.compileAssetDirs(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// expandEnv replaces ${var} and $var in s with the values of environment
+// variables, like os.ExpandEnv, and also returns the variables which are not
+// set.
+func expandEnv(s string) (string, []string) {
+	var unset []string
+	return os.Expand(s, func(v string) string {
+		val, ok := os.LookupEnv(v)
+		if !ok {
+			unset = append(unset, v)
+		}
+		return val
+	}), unset
+}
+
+// trimPath removes the first matching GoPkg prefix from file. Files which
+// match no prefix are returned unchanged.
+func (c *Config) trimPath(file string) string {
+	for _, p := range c.goPkg {
+		if !strings.ContainsAny(p, "*?[") {
+			if strings.HasPrefix(file, p) {
+				return strings.TrimPrefix(file, p)
+			}
+			continue
+		}
+		// Try the leading path elements of file, shortest first.
+		for i := strings.IndexByte(file, '/'); i >= 0; {
+			if ok, _ := path.Match(p, file[:i]); ok {
+				return file[i+1:]
+			}
+			j := strings.IndexByte(file[i+1:], '/')
+			if j < 0 {
+				break
+			}
+			i += j + 1
+		}
+	}
+	return file
+}
+
+// noLicenseDir reports whether file lies within one of NoLicenseDirs.
+func (c *Config) noLicenseDir(file string) bool {
+	return c.noLicenseDirIndex(file) >= 0
+}
+
+// noLicenseDirIndex returns the index of the first of NoLicenseDirs which
+// file lies within, or -1.
+func (c *Config) noLicenseDirIndex(file string) int {
+	for i, d := range c.NoLicenseDirs {
+		if file == d || strings.HasPrefix(file, d+"/") {
+			return i
+		}
+	}
+	return -1
+}
+
+// included reports whether the Accept and Reject rules select file for
+// license checking: a file must be accepted, by extension or pattern, and
+// not rejected by any of RejectExtensions, Reject or Ignore.
+func (c *Config) included(file string) bool {
+	return c.decide(file).included
+}
+
+// extensionSet returns the set of exts, each with a leading dot.
+func extensionSet(exts []string) map[string]bool {
+	set := make(map[string]bool, len(exts))
+	for _, e := range exts {
+		if !strings.HasPrefix(e, ".") {
+			e = "." + e
+		}
+		set[e] = true
+	}
+	return set
+}
+
+// match reports whether the contents of file carry one of the configured
+// licenses.
+func (c *Config) match(file string, contents []byte) bool {
+	return c.matchLicense(c.headers(file, contents)) >= 0
+}
+
+// matchLicense returns the index of the first license matching any of texts,
+// or -1 if none do.
+func (c *Config) matchLicense(texts [][]byte) int {
+	for i, re := range c.licensesRegexps {
+		var start time.Time
+		if c.profile != nil {
+			start = time.Now()
+		}
+		ok := matchTexts(re, texts)
+		if c.profile != nil {
+			c.profile[i].add(time.Since(start), ok)
+		}
+		if ok {
+			return i
+		}
+	}
+	return -1
+}
+
+// matchTexts reports whether re matches any of texts.
+func matchTexts(re *regexp.Regexp, texts [][]byte) bool {
+	for _, t := range texts {
+		if re.Match(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// forbidden reports whether the contents of file carry one of the Forbidden
+// licenses.
+func (c *Config) forbidden(file string, contents []byte) bool {
+	re, _ := firstMatch(c.forbiddenRegexps, c.headers(file, contents))
+	return re != nil
+}
+
+// forbiddenLicense returns the first Forbidden license carried by the
+// contents of file and its position, or nil if there is none.
+func (c *Config) forbiddenLicense(file string, contents []byte) (*License, int) {
+	texts := c.headers(file, contents)
+	for i, re := range c.forbiddenRegexps {
+		if matchTexts(re, texts) {
+			return &c.Forbidden[i], i
+		}
+	}
+	return nil, -1
+}
+
+// headers returns the texts of file which licenses are matched against:
+// the raw contents of the header region and, if comments are stripped, the
+// header text.
+func (c *Config) headers(file string, contents []byte) [][]byte {
+	contents, _ = c.maskVolatile(c.headerRegion(contents))
+	// Bundled assets keep their license in a leading "/*! ... */" banner
+	// which survives minification; match within it and ignore the rest.
+	if bannerExts[path.Ext(file)] {
+		if banner := leadingBanner(contents); banner != nil {
+			return [][]byte{banner, cStyle.stripHeader(banner)}
+		}
+	}
+	style, ok := c.styleFor(file, contents)
+	if !ok {
+		style = cStyle
+	}
+	// Markup carries its license in the first comment, which only a
+	// prolog like <?xml ...?> or <!DOCTYPE html> may precede.
+	if style == markupStyle {
+		comment := leadingMarkupComment(contents)
+		if comment == nil {
+			return nil
+		}
+		return [][]byte{comment, markupStyle.stripHeader(comment)}
+	}
+	texts := [][]byte{contents}
+	if c.StripComments {
+		if header := c.stripHeader(style, contents); header != nil {
+			texts = append(texts, header)
+		}
+	}
+	if c.UnwrapHeaders {
+		// Unwrap the plain header text if there is one, else the
+		// line comments at the top of the raw contents.
+		if len(texts) > 1 {
+			texts = append(texts, unwrap(texts[1], ""))
+		} else if style.line != "" {
+			texts = append(texts, unwrap(contents, style.line))
+		}
+	}
+	return texts
+}
+
+// firstMatch returns the first of res matching any of texts, and the text it
+// matches. It returns nil if none match.
+func firstMatch(res []*regexp.Regexp, texts [][]byte) (*regexp.Regexp, []byte) {
+	for _, re := range res {
+		for _, t := range texts {
+			if re.Match(t) {
+				return re, t
+			}
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,222 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNoLicenseDir(t *testing.T) {
+	c := &Config{NoLicenseDirs: []string{"vendor/", "pkg/foo/testdata"}}
+	if err := c.CompileRegexps(); err != nil {
+		t.Fatal(err)
+	}
+	for file, want := range map[string]bool{
+		"vendor":                   true,
+		"vendor/a/b.go":            true,
+		"vendored/a.go":            false,
+		"pkg/foo/testdata/x.go":    true,
+		"pkg/foo/testdata2/x.go":   false,
+		"pkg/bar/testdata/x.go":    false,
+		"cmds/core/vendor/main.go": false,
+	} {
+		if got := c.noLicenseDir(file); got != want {
+			t.Errorf("noLicenseDir(%q) = %t, want %t", file, got, want)
+		}
+	}
+}
+
+func TestLicenseFile(t *testing.T) {
+	c, err := loadConfig("testdata/licenses/config.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.licensesRegexps) != 2 {
+		t.Fatalf("got %d licenses, want 2", len(c.licensesRegexps))
+	}
+	for contents, want := range map[string]bool{
+		"// Copyright 2018 the u-root Authors. All rights reserved\n// Use of this source code is governed by a BSD-style\n// license that can be found in the LICENSE file.\n": true,
+		"// Copyright 2019 Google LLC.\n//\n// Licensed under the Apache License, Version 2.0 (the \"License\");\n":                                                             true,
+		"// Copyright 2019 Someone Else.\n": false,
+	} {
+		if got := c.match("x.go", []byte(contents)); got != want {
+			t.Errorf("match(%q) = %t, want %t", contents, got, want)
+		}
+	}
+}
+
+func TestTrimPath(t *testing.T) {
+	c := &Config{GoPkg: stringList{"github.com/u-root/u-root/", "modules/*", "tools/*/cmd"}}
+	if err := c.CompileRegexps(); err != nil {
+		t.Fatal(err)
+	}
+	for file, want := range map[string]string{
+		"github.com/u-root/u-root/cmds/ls.go": "cmds/ls.go",
+		"modules/foo/pkg/a.go":                "pkg/a.go",
+		"modules/a.go":                        "modules/a.go",
+		"tools/bar/cmd/main.go":               "main.go",
+		"tools/bar/main.go":                   "tools/bar/main.go",
+		"other/x.go":                          "other/x.go",
+	} {
+		if got := c.trimPath(file); got != want {
+			t.Errorf("trimPath(%q) = %q, want %q", file, got, want)
+		}
+	}
+}
+
+func TestGoPkgEnv(t *testing.T) {
+	os.Setenv("CHECKLICENSES_TEST_PKG", "github.com/u-root/u-root/")
+	defer os.Unsetenv("CHECKLICENSES_TEST_PKG")
+	os.Unsetenv("CHECKLICENSES_TEST_UNSET")
+
+	c := &Config{GoPkg: stringList{"$CHECKLICENSES_TEST_PKG"}}
+	if err := c.CompileRegexps(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := c.trimPath("github.com/u-root/u-root/a.go"), "a.go"; got != want {
+		t.Errorf("trimPath() = %q, want %q", got, want)
+	}
+
+	c = &Config{GoPkg: stringList{"${CHECKLICENSES_TEST_UNSET}"}}
+	if err := c.CompileRegexps(); err == nil {
+		t.Error("CompileRegexps() with GoPkg expanding to empty succeeded, want error")
+	}
+
+	*strictEnv = true
+	defer func() { *strictEnv = false }()
+	c = &Config{GoPkg: stringList{"src/${CHECKLICENSES_TEST_UNSET}"}}
+	if err := c.CompileRegexps(); err == nil {
+		t.Error("CompileRegexps() with -strict-env and an unset variable succeeded, want error")
+	}
+}
+
+func TestCompileRegexpsError(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		c    Config
+		want string
+	}{
+		{
+			name: "license",
+			c: Config{Licenses: []License{
+				{Lines: []string{"^// Copyright"}},
+				{Name: "apache", Lines: []string{"^// Copyright (", "// Apache"}},
+			}},
+			want: `Licenses[1] (apache): invalid regexp "^// Copyright (\n// Apache": `,
+		},
+		{
+			name: "accept",
+			c:    Config{Accept: []string{".*\\.go", "[a-"}},
+			want: `Accept[1]: invalid regexp "[a-": `,
+		},
+		{
+			name: "reject",
+			c:    Config{Reject: []string{"vendor/(.*"}},
+			want: `Reject[0]: invalid regexp "vendor/(.*": `,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.c.CompileRegexps()
+			if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+				t.Errorf("CompileRegexps() = %v, want an error starting with %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolvedRoundTrip(t *testing.T) {
+	c, err := loadConfig("testdata/licenses/config.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(c.resolved())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The printed configuration must not depend on its directory.
+	name := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(name, b, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	r, err := loadConfig(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := c.resolved().hash(policyOptions{})
+	if got, _ := r.resolved().hash(policyOptions{}); got != want {
+		t.Errorf("hash() of the resolved config = %s, want %s", got, want)
+	}
+}
+
+func TestExtensions(t *testing.T) {
+	c := &Config{
+		AcceptExtensions: []string{".go", "c"},
+		RejectExtensions: []string{".json"},
+		Accept:           []string{"Makefile", ".*\\.json"},
+		Reject:           []string{"vendor/.*"},
+	}
+	if err := c.CompileRegexps(); err != nil {
+		t.Fatal(err)
+	}
+	for file, want := range map[string]bool{
+		"cmds/core/ls/ls.go": true,
+		"a.c":                true,
+		"Makefile":           true,
+		"config.json":        false,
+		"README.md":          false,
+		"vendor/a/a.go":      false,
+	} {
+		if got := c.included(file); got != want {
+			t.Errorf("included(%q) = %t, want %t", file, got, want)
+		}
+	}
+}
+
+func TestIgnoreYears(t *testing.T) {
+	c := &Config{
+		Licenses: []License{{Lines: []string{
+			`^// Copyright 2018 the u-root Authors\. All rights reserved`,
+			`// Use of this source code is governed by a BSD-style`,
+		}}},
+		IgnoreYears: true,
+	}
+	if err := c.CompileRegexps(); err != nil {
+		t.Fatal(err)
+	}
+	for year, want := range map[string]bool{
+		"2018":       true,
+		"2012":       true,
+		"2017-2020":  true,
+		"2017, 2019": true,
+		"2017 - 20":  false,
+		"the year":   false,
+	} {
+		in := "// Copyright " + year + " the u-root Authors. All rights reserved\n// Use of this source code is governed by a BSD-style\n"
+		if got := c.match("a.go", []byte(in)); got != want {
+			t.Errorf("match(%q) = %t, want %t", year, got, want)
+		}
+	}
+}
+
+func TestConfigError(t *testing.T) {
+	defer func(b bool) { *zeroOnConfig = b }(*zeroOnConfig)
+	for _, tt := range []struct {
+		zero bool
+		want int
+	}{
+		{zero: false, want: exitUsage},
+		{zero: true, want: exitOK},
+	} {
+		*zeroOnConfig = tt.zero
+		if got := configError(errors.New("bad config")); got != tt.want {
+			t.Errorf("-exit-zero-on-config-error=%t: configError() = %d, want %d", tt.zero, got, tt.want)
+		}
+	}
+}
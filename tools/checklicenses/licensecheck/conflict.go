@@ -0,0 +1,32 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import "bytes"
+
+// conflictingHeaders reports whether the header region of file holds more
+// than one license header, as a bad merge leaves them stacked. A license
+// header is a run of lines starting with Copyright followed by license text,
+// so several holders listed together still make one header, and a bare
+// attribution like "Copyright 2009 The Go Authors." below the license makes
+// none.
+func (c *Config) conflictingHeaders(file string, contents []byte) bool {
+	style, ok := c.styleFor(file, contents)
+	if !ok {
+		return false
+	}
+	headers, copyright := 0, false
+	for _, line := range bytes.Split(c.stripHeader(style, contents), []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		switch {
+		case bytes.HasPrefix(line, []byte("Copyright ")):
+			copyright = true
+		case copyright && len(line) > 0:
+			headers++
+			copyright = false
+		}
+	}
+	return headers > 1
+}
@@ -0,0 +1,61 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import "testing"
+
+func TestConflictingHeaders(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		file string
+		in   string
+		want bool
+	}{
+		{
+			name: "single",
+			file: "a.go",
+			in:   "// Copyright 2018 the u-root Authors. All rights reserved\n// BSD\n\npackage a\n",
+		},
+		{
+			name: "several holders",
+			file: "a.go",
+			in:   "// Copyright 2018 the u-root Authors. All rights reserved\n// Copyright 2019 Google LLC\n// BSD\n\npackage a\n",
+		},
+		{
+			name: "stacked",
+			file: "a.go",
+			in:   "// Copyright 2018 the u-root Authors. All rights reserved\n// BSD\n\n// Copyright 2019 Google LLC\n//\n// Apache\n\npackage a\n",
+			want: true,
+		},
+		{
+			name: "stacked block comments",
+			file: "a.c",
+			in:   "/* Copyright 2018 X\n * BSD */\n/* Copyright 2019 Y\n * MIT */\nint x;\n",
+			want: true,
+		},
+		{
+			name: "attribution",
+			file: "a.go",
+			in:   "// Copyright 2018 X\n// BSD\n\n// Copied from Go.\n// Copyright 2009 The Go Authors. All rights reserved.\n\npackage a\n",
+		},
+		{
+			name: "below code",
+			file: "a.go",
+			in:   "// Copyright 2018 X\n// BSD\npackage a\n\n// Copyright 2019 Y\n",
+		},
+		{
+			name: "shell",
+			file: "a.sh",
+			in:   "#!/bin/sh\n# Copyright 2018 X\n# BSD\n#\n# Copyright 2018 X\n# BSD\necho\n",
+			want: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (&Config{}).conflictingHeaders(tt.file, []byte(tt.in)); got != tt.want {
+				t.Errorf("conflictingHeaders(%q) = %t, want %t", tt.in, got, tt.want)
+			}
+		})
+	}
+}
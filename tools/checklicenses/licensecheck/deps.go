@@ -0,0 +1,233 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+)
+
+// depModule is a module the build depends on.
+type depModule struct {
+	Path    string `json:"path"`
+	Version string `json:"version,omitempty"`
+	// Dir holds the source of the module, in vendor or in the module
+	// cache. It is empty if the module is not downloaded.
+	Dir string `json:"-"`
+	// License is the SPDX id of the license of the module, "unknown" if
+	// its license file is not recognized, or "none" if it has none.
+	License string `json:"license"`
+	// File is the license file the License was read from.
+	File string `json:"file,omitempty"`
+	// Allowed is set if the License is one of DepLicenses.
+	Allowed bool `json:"allowed"`
+}
+
+// vendorModules returns the modules listed in modules.txt, the manifest of a
+// vendor directory, which provide packages to the build.
+func vendorModules(r io.Reader, vendor string) ([]depModule, error) {
+	var mods []depModule
+	var cur *depModule
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := s.Text()
+		switch {
+		case strings.HasPrefix(line, "## "):
+			// Annotations, like "## explicit".
+		case strings.HasPrefix(line, "# "):
+			// "# path version", possibly followed by "=> replacement".
+			f := strings.Fields(line)
+			cur = &depModule{Path: f[1], Dir: filepath.Join(vendor, filepath.FromSlash(f[1]))}
+			if len(f) > 2 && f[2] != "=>" {
+				cur.Version = f[2]
+			}
+		case line != "" && cur != nil:
+			// The first package of the module puts it in the build.
+			mods = append(mods, *cur)
+			cur = nil
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read modules.txt: %v", err)
+	}
+	return mods, nil
+}
+
+// listModules returns the modules the build in the current directory depends
+// on: those in vendor/modules.txt if there is one, else those go list reports.
+func listModules(ctx context.Context) ([]depModule, error) {
+	if f, err := os.Open(filepath.Join("vendor", "modules.txt")); err == nil {
+		defer f.Close()
+		return vendorModules(f, "vendor")
+	}
+	out, err := exec.CommandContext(ctx, "go", "list", "-m", "-f", "{{if not .Main}}{{.Path}}\t{{.Version}}\t{{.Dir}}{{end}}", "all").Output()
+	if err != nil {
+		return nil, fmt.Errorf("error running go list: %v", err)
+	}
+	var mods []depModule
+	for _, line := range strings.Split(string(out), "\n") {
+		f := strings.Split(line, "\t")
+		if len(f) != 3 {
+			continue
+		}
+		mods = append(mods, depModule{Path: f[0], Version: f[1], Dir: f[2]})
+	}
+	return mods, nil
+}
+
+// licenseFileName matches the names of license files.
+var licenseFileName = regexp.MustCompile(`(?i)^(LICEN[CS]E|COPYING)([.-].*)?$`)
+
+// licenseFile returns the license file of the module in dir, or "" if it has
+// none.
+func licenseFile(dir string) string {
+	if dir == "" {
+		return ""
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+	for _, e := range entries {
+		if !e.IsDir() && licenseFileName.MatchString(e.Name()) {
+			return filepath.Join(dir, e.Name())
+		}
+	}
+	return ""
+}
+
+// depClassifiers recognize license texts by their telltale phrases, all of
+// which must appear, in order of precedence. Whitespace and case do not
+// matter.
+var depClassifiers = []struct {
+	id      string
+	phrases []string
+}{
+	{"AGPL-3.0", []string{"GNU AFFERO GENERAL PUBLIC LICENSE"}},
+	{"LGPL-3.0", []string{"GNU LESSER GENERAL PUBLIC LICENSE", "Version 3"}},
+	{"LGPL-2.1", []string{"GNU LESSER GENERAL PUBLIC LICENSE", "Version 2.1"}},
+	{"GPL-3.0", []string{"GNU GENERAL PUBLIC LICENSE", "Version 3"}},
+	{"GPL-2.0", []string{"GNU GENERAL PUBLIC LICENSE", "Version 2"}},
+	{"MPL-2.0", []string{"Mozilla Public License", "Version 2.0"}},
+	{"Apache-2.0", []string{"Apache License", "Version 2.0"}},
+	{"BSD-3-Clause", []string{"Redistribution and use in source and binary forms", "Neither the name"}},
+	{"BSD-3-Clause", []string{"Redistribution and use in source and binary forms", "names of its contributors"}},
+	{"BSD-2-Clause", []string{"Redistribution and use in source and binary forms"}},
+	{"MIT", []string{"Permission is hereby granted, free of charge"}},
+	{"ISC", []string{"Permission to use, copy, modify, and"}},
+	{"Unlicense", []string{"This is free and unencumbered software released into the public domain"}},
+}
+
+// classifyLicense returns the SPDX id of the license text in contents, or
+// "unknown". An SPDX tag takes precedence over the text.
+func classifyLicense(contents []byte) string {
+	if tag, ok := spdxTag(contents); ok {
+		return tag
+	}
+	text := strings.ToLower(strings.Join(strings.Fields(string(contents)), " "))
+	for _, c := range depClassifiers {
+		all := true
+		for _, p := range c.phrases {
+			if !strings.Contains(text, strings.ToLower(p)) {
+				all = false
+				break
+			}
+		}
+		if all {
+			return c.id
+		}
+	}
+	return "unknown"
+}
+
+// inventory classifies the license of each of mods, and whether DepLicenses
+// allow it. With no DepLicenses, every license is allowed.
+func (c *Config) inventory(mods []depModule) ([]depModule, error) {
+	for i := range mods {
+		m := &mods[i]
+		m.License = "none"
+		if m.File = licenseFile(m.Dir); m.File != "" {
+			contents, err := os.ReadFile(m.File)
+			if err != nil {
+				return nil, err
+			}
+			m.License = classifyLicense(contents)
+		}
+		m.Allowed = len(c.DepLicenses) == 0
+		for _, id := range c.DepLicenses {
+			// License identifiers are case-insensitive.
+			if strings.EqualFold(id, m.License) {
+				m.Allowed = true
+			}
+		}
+		m.File = filepath.ToSlash(m.File)
+	}
+	return mods, nil
+}
+
+// writeInventory writes the modules with their licenses to w, as a table, or
+// as JSON if format is "json".
+func writeInventory(w io.Writer, format string, mods []depModule) error {
+	if format == "json" {
+		b, err := json.MarshalIndent(struct {
+			Modules []depModule `json:"modules"`
+		}{append([]depModule{}, mods...)}, "", "\t")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", b)
+		return err
+	}
+	var b bytes.Buffer
+	tw := tabwriter.NewWriter(&b, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "MODULE\tVERSION\tLICENSE\tFILE")
+	for _, m := range mods {
+		license := m.License
+		if !m.Allowed {
+			license += " (not allowed)"
+		}
+		file := m.File
+		if file == "" {
+			file = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", m.Path, m.Version, license, file)
+	}
+	tw.Flush()
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// checkDeps writes the license inventory of the modules the build depends on
+// to w, for -deps, and returns exitViolations if any license is not allowed.
+func checkDeps(ctx context.Context, w io.Writer, format string, config *Config) int {
+	mods, err := listModules(ctx)
+	if err == nil {
+		mods, err = config.inventory(mods)
+	}
+	if err == nil {
+		err = writeInventory(w, format, mods)
+	}
+	if err != nil {
+		log.Printf("-deps: %v", err)
+		return exitIO
+	}
+	for _, m := range mods {
+		if !m.Allowed {
+			return exitViolations
+		}
+	}
+	return exitOK
+}
@@ -0,0 +1,96 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestClassifyLicense(t *testing.T) {
+	for in, want := range map[string]string{
+		"Apache License\n   Version 2.0, January 2004":                                    "Apache-2.0",
+		"Permission is hereby granted,\nfree of charge, to any person":                    "MIT",
+		"Redistribution and use in source and binary forms... Neither the name of Google": "BSD-3-Clause",
+		"Redistribution and use in source and binary forms, with or without":              "BSD-2-Clause",
+		"GNU LESSER GENERAL PUBLIC LICENSE\nVersion 2.1, February 1999":                   "LGPL-2.1",
+		"GNU GENERAL PUBLIC LICENSE\nVersion 2, June 1991":                                "GPL-2.0",
+		"SPDX-License-Identifier: MPL-2.0\n":                                              "MPL-2.0",
+		"All rights reserved.":                                                            "unknown",
+	} {
+		if got := classifyLicense([]byte(in)); got != want {
+			t.Errorf("classifyLicense(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestCheckDeps(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	for name, contents := range map[string]string{
+		"vendor/modules.txt": strings.Join([]string{
+			"# example.com/mit v1.0.0",
+			"## explicit",
+			"example.com/mit",
+			"# example.com/gpl v0.1.0 => ../gpl v0.1.1",
+			"example.com/gpl/sub",
+			"# example.com/bare v1.2.3",
+			"example.com/bare",
+			"# example.com/unused v1.0.0",
+			"## explicit",
+			"",
+		}, "\n"),
+		"vendor/example.com/mit/LICENSE":    "MIT License\n\nPermission is hereby granted, free of charge",
+		"vendor/example.com/gpl/COPYING":    "GNU GENERAL PUBLIC LICENSE\nVersion 3, 29 June 2007",
+		"vendor/example.com/bare/bare.go":   "package bare\n",
+		"vendor/example.com/mit/mit.go":     "package mit\n",
+		"vendor/example.com/gpl/sub/sub.go": "package sub\n",
+	} {
+		if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte(contents), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c := &Config{DepLicenses: []string{"mit", "BSD-3-Clause"}}
+	mods, err := listModules(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := c.inventory(mods)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []depModule{
+		{Path: "example.com/mit", Version: "v1.0.0", Dir: filepath.Join("vendor", "example.com", "mit"), License: "MIT", File: "vendor/example.com/mit/LICENSE", Allowed: true},
+		{Path: "example.com/gpl", Version: "v0.1.0", Dir: filepath.Join("vendor", "example.com", "gpl"), License: "GPL-3.0", File: "vendor/example.com/gpl/COPYING"},
+		{Path: "example.com/bare", Version: "v1.2.3", Dir: filepath.Join("vendor", "example.com", "bare"), License: "none"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("inventory() = %+v, want %+v", got, want)
+	}
+	var b bytes.Buffer
+	if code := checkDeps(context.Background(), &b, "text", c); code != exitViolations {
+		t.Errorf("checkDeps() = %d, want %d", code, exitViolations)
+	}
+	if !strings.Contains(b.String(), "GPL-3.0 (not allowed)") {
+		t.Errorf("checkDeps() wrote\n%s\nwant GPL-3.0 marked as not allowed", b.String())
+	}
+	if code := checkDeps(context.Background(), &b, "json", &Config{}); code != exitOK {
+		t.Errorf("checkDeps() without DepLicenses = %d, want %d", code, exitOK)
+	}
+}
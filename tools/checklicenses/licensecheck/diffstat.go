@@ -0,0 +1,100 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// statRow counts the violations in one group, by bucket.
+type statRow struct {
+	key     string
+	total   int
+	buckets map[bucket]int
+}
+
+// groupViolations counts violations by the group key returns for each.
+// Groups with the most violations come first.
+func groupViolations(violations []violation, key func(violation) string) []statRow {
+	rows := map[string]*statRow{}
+	for _, v := range violations {
+		k := key(v)
+		r, ok := rows[k]
+		if !ok {
+			r = &statRow{key: k, buckets: map[bucket]int{}}
+			rows[k] = r
+		}
+		r.total++
+		r.buckets[v.bucket]++
+	}
+	sorted := make([]statRow, 0, len(rows))
+	for _, r := range rows {
+		sorted = append(sorted, *r)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].total != sorted[j].total {
+			return sorted[i].total > sorted[j].total
+		}
+		return sorted[i].key < sorted[j].key
+	})
+	return sorted
+}
+
+// extension returns the group of file by extension, like "*.go", or "(none)".
+func extension(file string) string {
+	if e := path.Ext(file); e != "" {
+		return "*" + e
+	}
+	return "(none)"
+}
+
+// printDiffstat prints the number of violations by top-level directory and
+// by file extension, with their buckets, and the total.
+func (c *Config) printDiffstat(w io.Writer, violations []violation) {
+	topDir := func(v violation) string {
+		p := c.trimPath(v.file)
+		if i := strings.IndexByte(p, '/'); i >= 0 {
+			return p[:i] + "/"
+		}
+		return "."
+	}
+	ext := func(v violation) string { return extension(v.file) }
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	for _, g := range []struct {
+		title string
+		key   func(violation) string
+	}{
+		{"DIRECTORY", topDir},
+		{"EXTENSION", ext},
+	} {
+		fmt.Fprintf(tw, "%s\tVIOLATIONS\tBUCKETS\n", g.title)
+		for _, r := range groupViolations(violations, g.key) {
+			fmt.Fprintf(tw, "%s\t%d\t%s\n", r.key, r.total, r.describe())
+		}
+		fmt.Fprintln(tw)
+	}
+	all := groupViolations(violations, func(violation) string { return "total" })
+	if len(all) == 0 {
+		all = []statRow{{key: "total"}}
+	}
+	fmt.Fprintf(tw, "%s\t%d\t%s\n", "TOTAL", all[0].total, all[0].describe())
+	tw.Flush()
+}
+
+// describe lists the counts of the row by bucket, in bucket order.
+func (r statRow) describe() string {
+	var s []string
+	for _, b := range buckets {
+		if n := r.buckets[b]; n > 0 {
+			s = append(s, fmt.Sprintf("%s %d", b, n))
+		}
+	}
+	return strings.Join(s, ", ")
+}
@@ -0,0 +1,37 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiffstat(t *testing.T) {
+	c := &Config{}
+	var b strings.Builder
+	c.printDiffstat(&b, []violation{
+		{file: "pkg/a/a.go", bucket: bucketMissing},
+		{file: "pkg/b/b.go", bucket: bucketMissing},
+		{file: "pkg/b/b.sh", bucket: bucketFormatting},
+		{file: "cmds/x/x.go", bucket: bucketForbidden},
+		{file: "Makefile", bucket: bucketMissing},
+	})
+	want := `DIRECTORY  VIOLATIONS  BUCKETS
+pkg/       3           missing 2, formatting 1
+.          1           missing 1
+cmds/      1           forbidden 1
+
+EXTENSION  VIOLATIONS  BUCKETS
+*.go       3           missing 2, forbidden 1
+(none)     1           missing 1
+*.sh       1           formatting 1
+
+TOTAL  5  missing 3, forbidden 1, formatting 1
+`
+	if got := b.String(); got != want {
+		t.Errorf("printDiffstat() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"os"
+	"path"
+	"strings"
+	"sync"
+)
+
+// dirConfigName is the name of the configuration file which governs the
+// directory holding it and all its subdirectories, overriding the
+// configuration given with -c.
+const dirConfigName = ".licenserc.json"
+
+// scopedConfig is a configuration together with the directory it governs.
+type scopedConfig struct {
+	*Config
+	// dir is the directory holding the configuration file, or "" for
+	// the top-level configuration.
+	dir string
+}
+
+// rel returns file relative to the directory governed by the configuration.
+// Rules in a per-directory configuration are relative to its directory.
+func (s *scopedConfig) rel(file string) string {
+	if s.dir == "" {
+		return file
+	}
+	return strings.TrimPrefix(file, s.dir+"/")
+}
+
+// configCache finds the configuration governing each directory. Every
+// configuration file is loaded and compiled once.
+type configCache struct {
+	top *scopedConfig
+
+	mu   sync.Mutex
+	dirs map[string]*scopedConfig
+
+	// trace, if set, makes selectFiles record the rule deciding on each
+	// file in traced, for -trace-rules.
+	trace  bool
+	traced []tracedRule
+
+	// profile, if set, collects match statistics for the configurations
+	// loaded, for -profile-config.
+	profile bool
+
+	// opts are how the selected files are read and checked.
+	opts Options
+}
+
+func newConfigCache(top *Config, opts Options) *configCache {
+	return &configCache{
+		top:  &scopedConfig{Config: top},
+		dirs: map[string]*scopedConfig{},
+		opts: opts,
+	}
+}
+
+// forFile returns the configuration of the nearest ancestor directory of
+// file holding a dirConfigName, or the top-level configuration if there is
+// none.
+func (cc *configCache) forFile(file string) (*scopedConfig, error) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	return cc.forDir(path.Dir(file))
+}
+
+func (cc *configCache) forDir(dir string) (*scopedConfig, error) {
+	if c, ok := cc.dirs[dir]; ok {
+		return c, nil
+	}
+	name := path.Join(dir, dirConfigName)
+	var c *scopedConfig
+	if _, err := os.Stat(name); err == nil {
+		config, err := loadConfig(name)
+		if err != nil {
+			return nil, err
+		}
+		if cc.profile {
+			config.enableProfile()
+		}
+		c = &scopedConfig{Config: config, dir: dir}
+		if dir == "." {
+			c.dir = ""
+		}
+	} else if dir == "." || dir == "/" {
+		c = cc.top
+	} else {
+		var err error
+		if c, err = cc.forDir(path.Dir(dir)); err != nil {
+			return nil, err
+		}
+	}
+	cc.dirs[dir] = c
+	return c, nil
+}
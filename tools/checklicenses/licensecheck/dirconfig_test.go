@@ -0,0 +1,57 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestConfigCache(t *testing.T) {
+	dir := t.TempDir()
+	sub := filepath.Join(dir, "team", "pkg")
+	if err := os.MkdirAll(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "team", dirConfigName), []byte(`{"accept": ["pkg/.*\\.py"]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	top := &Config{Accept: []string{".*\\.go"}}
+	if err := top.CompileRegexps(); err != nil {
+		t.Fatal(err)
+	}
+	cc := newConfigCache(top, Options{})
+
+	c, err := cc.forFile(filepath.Join(dir, "main.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Config != top {
+		t.Errorf("forFile(main.go) did not return the top-level config")
+	}
+
+	file := filepath.Join(sub, "a.py")
+	team, err := cc.forFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if team.Config == top {
+		t.Fatalf("forFile(%s) returned the top-level config", file)
+	}
+	if got, want := team.rel(file), "pkg/a.py"; got != want {
+		t.Errorf("rel(%s) = %q, want %q", file, got, want)
+	}
+	if !team.included(team.rel(file)) {
+		t.Errorf("%s is not included by %s", file, dirConfigName)
+	}
+	again, err := cc.forFile(filepath.Join(sub, "b.py"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again != team {
+		t.Errorf("forFile(b.py) loaded %s again", dirConfigName)
+	}
+}
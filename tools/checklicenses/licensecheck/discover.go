@@ -0,0 +1,164 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// headerGroup is a distinct license header and the files carrying it.
+type headerGroup struct {
+	// header is the text of the header with comment markers removed.
+	header string
+	// files are the files carrying the header, sorted.
+	files []string
+	// candidates are the candidates of files, in the same order.
+	candidates []candidate
+}
+
+// header returns the text of the header at the top of the candidate, with
+// comment markers removed. It returns false for directories.
+func (cd candidate) header() (string, bool, error) {
+	name, contents, err := cd.read()
+	if err != nil || contents == nil {
+		return "", false, err
+	}
+	style, ok := cd.config.styleFor(name, contents)
+	if !ok {
+		style = cStyle
+	}
+	return string(cd.config.stripHeader(style, contents)), true, nil
+}
+
+// discover groups candidates by the header at their top, most common header
+// first. Files without a header are grouped under the empty header.
+// byConfig also tells apart the candidates governed by different
+// configurations, so that each group is checked alike.
+func discover(candidates []candidate, byConfig bool) ([]headerGroup, error) {
+	type key struct {
+		header string
+		config *Config
+	}
+	byHeader := map[key][]candidate{}
+	for _, cd := range candidates {
+		h, ok, err := cd.header()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		k := key{header: h}
+		if byConfig {
+			k.config = cd.config
+		}
+		byHeader[k] = append(byHeader[k], cd)
+	}
+	groups := make([]headerGroup, 0, len(byHeader))
+	for k, cds := range byHeader {
+		sort.Slice(cds, func(i, j int) bool { return cds[i].file < cds[j].file })
+		g := headerGroup{header: k.header, candidates: cds}
+		for _, cd := range cds {
+			g.files = append(g.files, cd.file)
+		}
+		groups = append(groups, g)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if len(groups[i].files) != len(groups[j].files) {
+			return len(groups[i].files) > len(groups[j].files)
+		}
+		if groups[i].header != groups[j].header {
+			return groups[i].header < groups[j].header
+		}
+		return groups[i].files[0] < groups[j].files[0]
+	})
+	return groups, nil
+}
+
+// sample returns the candidate of a file carrying the header: the smallest
+// path if rng is nil, or else one picked with rng.
+func (g headerGroup) sample(rng *rand.Rand) candidate {
+	if rng == nil {
+		return g.candidates[0]
+	}
+	return g.candidates[rng.Intn(len(g.candidates))]
+}
+
+// printDiscovered prints each header group with its number of files and a
+// sample file, which is picked reproducibly from seed if it is not 0.
+func printDiscovered(w io.Writer, groups []headerGroup, seed int64, display func(string) string) {
+	var rng *rand.Rand
+	if seed != 0 {
+		rng = rand.New(rand.NewSource(seed))
+	}
+	for _, g := range groups {
+		fmt.Fprintf(w, "%d files, e.g. %s\n", len(g.files), display(g.sample(rng).file))
+		if g.header == "" {
+			fmt.Fprintln(w, "\t(no header)")
+			continue
+		}
+		for _, l := range strings.Split(strings.TrimSuffix(g.header, "\n"), "\n") {
+			fmt.Fprintf(w, "\t%s\n", l)
+		}
+	}
+}
+
+// sampledGroup is the result of checking one sample of a header group.
+type sampledGroup struct {
+	headerGroup
+	sample candidate
+	// bucket is the bucket of the violation in the sample, or "" if it
+	// conforms.
+	bucket bucket
+}
+
+// sampleByHeader groups candidates by header and configuration, and checks
+// one sample of each group, picked as by printDiscovered, with timeout.
+func sampleByHeader(ctx context.Context, candidates []candidate, seed int64, timeout time.Duration) ([]sampledGroup, error) {
+	groups, err := discover(candidates, true)
+	if err != nil {
+		return nil, err
+	}
+	var rng *rand.Rand
+	if seed != 0 {
+		rng = rand.New(rand.NewSource(seed))
+	}
+	sampled := make([]sampledGroup, 0, len(groups))
+	for _, g := range groups {
+		s := sampledGroup{headerGroup: g, sample: g.sample(rng)}
+		v, err := s.sample.checkTimeout(ctx, timeout)
+		if err != nil {
+			return nil, err
+		}
+		s.bucket = v.bucket
+		sampled = append(sampled, s)
+	}
+	return sampled, nil
+}
+
+// printSampled prints the result of each sampled group, attributed to all its
+// files, and how many files were actually checked.
+func printSampled(w io.Writer, groups []sampledGroup, display func(string) string) {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "RESULT\tFILES\tSAMPLE\n")
+	files := 0
+	for _, g := range groups {
+		result := "pass"
+		if g.bucket != "" {
+			result = string(g.bucket)
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%s\n", result, len(g.files), display(g.sample.file))
+		files += len(g.files)
+	}
+	tw.Flush()
+	fmt.Fprintf(w, "SAMPLED: checked %d of %d files, one per distinct header; the other results are assumed\n", len(groups), files)
+}
@@ -0,0 +1,98 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDiscover(t *testing.T) {
+	dir := t.TempDir()
+	var candidates []candidate
+	for name, contents := range map[string]string{
+		"c.go": "// Copyright X\n\npackage a\n",
+		"a.go": "// Copyright X\n\npackage a\n",
+		"b.go": "// Copyright X\n\npackage a\n",
+		"d.go": "// Copyright Y\npackage a\n",
+		"e.go": "package a\n",
+	} {
+		file := filepath.Join(dir, name)
+		if err := os.WriteFile(file, []byte(contents), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		candidates = append(candidates, candidate{file: file, config: &Config{}})
+	}
+	groups, err := discover(candidates, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(groups) != 3 {
+		t.Fatalf("discover() = %d groups, want 3", len(groups))
+	}
+	if g := groups[0]; g.header != "Copyright X\n" || len(g.files) != 3 || g.sample(nil).file != filepath.Join(dir, "a.go") {
+		t.Errorf("discover()[0] = %+v, want Copyright X in a.go, b.go, c.go", g)
+	}
+	if g := groups[1]; g.header != "" {
+		t.Errorf("discover()[1].header = %q, want the empty header first among ties", g.header)
+	}
+
+	print := func(seed int64) string {
+		var b bytes.Buffer
+		printDiscovered(&b, groups, seed, filepath.Base)
+		return b.String()
+	}
+	for _, seed := range []int64{0, 42} {
+		if a, b := print(seed), print(seed); a != b {
+			t.Errorf("printDiscovered(seed %d) differs between runs:\n%s\n%s", seed, a, b)
+		}
+	}
+}
+
+func TestSampleByHeader(t *testing.T) {
+	dir := t.TempDir()
+	c := &Config{Licenses: []License{{Lines: []string{"^// Copyright X"}}}}
+	if err := c.CompileRegexps(); err != nil {
+		t.Fatal(err)
+	}
+	strict := &Config{Licenses: []License{{Lines: []string{"^// Copyright Y"}}}}
+	if err := strict.CompileRegexps(); err != nil {
+		t.Fatal(err)
+	}
+	var candidates []candidate
+	for _, f := range []struct {
+		name, contents string
+		config         *Config
+	}{
+		{"a.go", "// Copyright X\npackage a\n", c},
+		{"b.go", "// Copyright X\npackage a\n", c},
+		{"c.go", "// Copyright X\npackage a\n", strict},
+		{"d.go", "package a\n", c},
+	} {
+		file := filepath.Join(dir, f.name)
+		if err := os.WriteFile(file, []byte(f.contents), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		candidates = append(candidates, candidate{file: file, config: f.config})
+	}
+	groups, err := sampleByHeader(context.Background(), candidates, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	printSampled(&b, groups, filepath.Base)
+	want := `RESULT   FILES  SAMPLE
+pass     2      a.go
+missing  1      d.go
+missing  1      c.go
+SAMPLED: checked 3 of 4 files, one per distinct header; the other results are assumed
+`
+	if got := b.String(); got != want {
+		t.Errorf("printSampled() =\n%s\nwant\n%s", got, want)
+	}
+}
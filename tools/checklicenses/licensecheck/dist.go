@@ -0,0 +1,114 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+)
+
+// checkDistFiles checks that the required DistFiles are among files and
+// contain their license.
+func (c *Config) checkDistFiles(files []string) ([]violation, error) {
+	if len(c.DistFiles) == 0 {
+		return nil, nil
+	}
+	byDir := filesByDir(files)
+	var modules []string
+	for _, f := range files {
+		if path.Base(f) == "go.mod" {
+			modules = append(modules, path.Dir(f))
+		}
+	}
+
+	var v []violation
+	for i, d := range c.DistFiles {
+		license := d.License.id("DistFiles", i)
+		roots := []string{"."}
+		if d.PerModule {
+			roots = modules
+		}
+		for _, dir := range roots {
+			var found []string
+			for _, base := range byDir[dir] {
+				if ok, _ := path.Match(d.Name, base); ok {
+					found = append(found, path.Join(dir, base))
+				}
+			}
+			if len(found) == 0 {
+				v = append(v, violation{file: path.Join(dir, d.Name), bucket: bucketDistMissing, license: license, enforceAfter: d.License.enforceAfter})
+				continue
+			}
+			for _, f := range found {
+				contents, err := os.ReadFile(f)
+				if err != nil {
+					return nil, fmt.Errorf("cannot read %s: %v", f, err)
+				}
+				if !d.license.Match(contents) {
+					v = append(v, violation{file: f, bucket: bucketDistContent, license: license, enforceAfter: d.License.enforceAfter})
+				}
+			}
+		}
+	}
+	return v, nil
+}
+
+// filesByDir maps every directory holding files, and each of its ancestors,
+// to the base names of the files directly within it.
+func filesByDir(files []string) map[string][]string {
+	byDir := map[string][]string{}
+	for _, f := range files {
+		dir, base := path.Dir(f), path.Base(f)
+		byDir[dir] = append(byDir[dir], base)
+		for dir != "." && dir != "/" {
+			dir = path.Dir(dir)
+			if _, ok := byDir[dir]; !ok {
+				byDir[dir] = nil
+			}
+		}
+	}
+	return byDir
+}
+
+// checkDirRules checks that every directory matching a DirRule holds one of
+// the files it requires.
+func (c *Config) checkDirRules(files []string) []violation {
+	if len(c.DirRules) == 0 {
+		return nil
+	}
+	byDir := filesByDir(files)
+	dirs := make([]string, 0, len(byDir))
+	for dir := range byDir {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	var v []violation
+	for _, r := range c.DirRules {
+		for _, dir := range dirs {
+			if ok, _ := path.Match(r.DirGlob, dir); !ok {
+				continue
+			}
+			if !hasAny(byDir[dir], r.RequireOneOf) {
+				v = append(v, violation{file: dir, bucket: bucketDirMissing, enforceAfter: r.enforceAfter})
+			}
+		}
+	}
+	return v
+}
+
+// hasAny reports whether any of names is in files.
+func hasAny(files, names []string) bool {
+	for _, f := range files {
+		for _, n := range names {
+			if f == n {
+				return true
+			}
+		}
+	}
+	return false
+}
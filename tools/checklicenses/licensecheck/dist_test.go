@@ -0,0 +1,81 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCheckDistFiles(t *testing.T) {
+	dir := t.TempDir()
+	for name, contents := range map[string]string{
+		"LICENSE":    "BSD 3-Clause License\n",
+		"m/go.mod":   "module m\n",
+		"m/COPYING":  "GNU GENERAL PUBLIC LICENSE\n",
+		"n/go.mod":   "module n\n",
+		"n/main.go":  "package main\n",
+		"x/LICENSE2": "BSD 3-Clause License\n",
+	} {
+		name = filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte(contents), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	c := &Config{DistFiles: []DistFile{
+		{Name: "LICENSE*", License: License{Lines: []string{"BSD"}}},
+		{Name: "COPYING", License: License{Lines: []string{"BSD"}}, PerModule: true},
+	}}
+	if err := c.CompileRegexps(); err != nil {
+		t.Fatal(err)
+	}
+	got, err := c.checkDistFiles([]string{"LICENSE", "m/go.mod", "m/COPYING", "n/go.mod", "n/main.go", "x/LICENSE2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []violation{
+		{file: "m/COPYING", bucket: bucketDistContent, license: "DistFiles[1]"},
+		{file: "n/COPYING", bucket: bucketDistMissing, license: "DistFiles[1]"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("checkDistFiles() = %v, want %v", got, want)
+	}
+}
+
+func TestCheckDirRules(t *testing.T) {
+	c := &Config{DirRules: []DirRule{{DirGlob: "vendor/*/*", RequireOneOf: []string{"LICENSE", "COPYING"}}}}
+	if err := c.CompileRegexps(); err != nil {
+		t.Fatal(err)
+	}
+	got := c.checkDirRules([]string{
+		"vendor/github.com/a/LICENSE",
+		"vendor/github.com/a/a.go",
+		"vendor/github.com/b/COPYING",
+		"vendor/github.com/c/c.go",
+		"vendor/golang.org/x/sub/x.go",
+		"main.go",
+	})
+	want := []violation{
+		{file: "vendor/github.com/c", bucket: bucketDirMissing},
+		{file: "vendor/golang.org/x", bucket: bucketDirMissing},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("checkDirRules() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// duplicateHeaders returns the range of contents holding copies of its
+// first comment block, which repeat it right below, separated only by blank
+// lines, as running a header insertion script twice leaves them. Only
+// blocks naming a Copyright count as headers. It returns false if there are
+// no copies.
+func (s commentStyle) duplicateHeaders(contents []byte) (int, int, bool) {
+	end, ok := s.headerEnd(contents)
+	if !ok {
+		return 0, 0, false
+	}
+	start := 0
+	if bytes.HasPrefix(contents, []byte("#!")) {
+		start = bytes.IndexByte(contents, '\n') + 1
+	}
+	// Copies without a blank line in between make a single block, which
+	// repeats its leading lines.
+	lines := bytes.SplitAfter(contents[start:end], []byte("\n"))
+	if len(lines[len(lines)-1]) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+	first := end
+	for n := 1; n <= len(lines)/2; n++ {
+		if len(lines)%n == 0 && repeats(lines, n) {
+			first = start + len(bytes.Join(lines[:n], nil))
+			break
+		}
+	}
+	header := bytes.TrimSpace(contents[start:first])
+	if !bytes.Contains(header, []byte("Copyright")) {
+		return 0, 0, false
+	}
+	for {
+		next := end
+		for n := blankLines(contents[next:]); n > 0; n-- {
+			next += bytes.IndexByte(contents[next:], '\n') + 1
+		}
+		if bytes.HasPrefix(contents[next:], []byte("#!")) {
+			break
+		}
+		copyEnd, ok := s.headerEnd(contents[next:])
+		if !ok || !bytes.Equal(bytes.TrimSpace(contents[next:next+copyEnd]), header) {
+			break
+		}
+		end = next + copyEnd
+	}
+	return first, end, end > first
+}
+
+// repeats reports whether lines are their first n lines over and over.
+func repeats(lines [][]byte, n int) bool {
+	for i := n; i < len(lines); i++ {
+		if !bytes.Equal(bytes.TrimSpace(lines[i]), bytes.TrimSpace(lines[i-n])) {
+			return false
+		}
+	}
+	return true
+}
+
+// duplicateHeader reports whether file repeats its license header.
+func (c *Config) duplicateHeader(file string, contents []byte) bool {
+	return c.duplicateLine(file, contents) > 0
+}
+
+// duplicateLine returns the 1-based line of the first copy of the license
+// header of file, or 0 if it has none.
+func (c *Config) duplicateLine(file string, contents []byte) int {
+	style, ok := c.styleFor(file, contents)
+	if !ok {
+		return 0
+	}
+	first, _, ok := style.duplicateHeaders(contents)
+	if !ok {
+		return 0
+	}
+	// The copy may follow blank lines.
+	copied := bytes.TrimLeft(contents[first:], " \t\r\n")
+	return bytes.Count(contents[:len(contents)-len(copied)], []byte("\n")) + 1
+}
+
+// collapseHeaders returns contents without the copies of their license
+// header.
+func (c *Config) collapseHeaders(file string, contents []byte) ([]byte, error) {
+	style, ok := c.styleFor(file, contents)
+	if !ok {
+		return nil, fmt.Errorf("cannot fix %s: unknown comment syntax", file)
+	}
+	start, end, ok := style.duplicateHeaders(contents)
+	if !ok {
+		return nil, fmt.Errorf("cannot fix %s: no duplicate header found", file)
+	}
+	fixed := append([]byte(nil), contents[:start]...)
+	return append(fixed, contents[end:]...), nil
+}
@@ -0,0 +1,89 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import "testing"
+
+func TestDuplicateHeader(t *testing.T) {
+	c := &Config{Licenses: []License{{Lines: []string{"^// Copyright 2026 X", "// BSD"}}}}
+	if err := c.CompileRegexps(); err != nil {
+		t.Fatal(err)
+	}
+	const header = "// Copyright 2026 X\n// BSD\n"
+	for _, tt := range []struct {
+		name string
+		file string
+		in   string
+		want bucket
+		// line is where the first copy starts.
+		line int
+		// fixed is the result of collapsing the duplicates.
+		fixed string
+	}{
+		{
+			name: "single",
+			file: "a.go",
+			in:   header + "\npackage a\n",
+		},
+		{
+			name:  "twice",
+			file:  "a.go",
+			in:    header + "\n" + header + "\npackage a\n",
+			want:  bucketDuplicate,
+			line:  4,
+			fixed: header + "\npackage a\n",
+		},
+		{
+			name:  "adjacent",
+			file:  "a.go",
+			in:    header + header + "package a\n",
+			want:  bucketDuplicate,
+			line:  3,
+			fixed: header + "package a\n",
+		},
+		{
+			name:  "three times",
+			file:  "a.go",
+			in:    header + "\n" + header + "\n\n" + header + "\npackage a\n",
+			want:  bucketDuplicate,
+			line:  4,
+			fixed: header + "\npackage a\n",
+		},
+		{
+			name:  "shebang",
+			file:  "run.sh",
+			in:    "#!/bin/sh\n# Copyright 2026 X\n# BSD\n\n# Copyright 2026 X\n# BSD\necho\n",
+			want:  bucketMissing,
+			fixed: "#!/bin/sh\n# Copyright 2026 X\n# BSD\necho\n",
+		},
+		{
+			name: "different blocks",
+			file: "a.go",
+			in:   header + "\n// Copyright 2026 Y\n// BSD\n\npackage a\n",
+			want: bucketConflicting,
+		},
+		{
+			name: "repeated doc comment",
+			file: "a.go",
+			in:   header + "\n// Package a.\n\n// Package a.\npackage a\n",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if v := (candidate{file: tt.file, config: c}).classify(tt.file, []byte(tt.in)); v.bucket != tt.want || v.line != tt.line {
+				t.Errorf("classify(%q) = %q at line %d, want %q at line %d", tt.in, v.bucket, v.line, tt.want, tt.line)
+			}
+			fixed, err := c.collapseHeaders(tt.file, []byte(tt.in))
+			if tt.fixed == "" {
+				if err == nil {
+					t.Errorf("collapseHeaders(%q) = %q, want error", tt.in, fixed)
+				}
+				return
+			}
+			if err != nil || string(fixed) != tt.fixed {
+				t.Errorf("collapseHeaders(%q) = %q, %v, want %q", tt.in, fixed, err, tt.fixed)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"fmt"
+	"time"
+)
+
+// dateLayout is the layout of EnforceAfter dates.
+const dateLayout = "2006-01-02"
+
+// parseDate parses an EnforceAfter date. The empty string is the zero time,
+// which is always enforced.
+func parseDate(what, s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(dateLayout, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s: invalid EnforceAfter date %q, want YYYY-MM-DD", what, s)
+	}
+	return t, nil
+}
+
+// compileEnforceAfter parses the EnforceAfter dates of the configuration.
+func (c *Config) compileEnforceAfter() error {
+	c.enforceAfter = make(map[bucket]time.Time, len(c.EnforceAfter))
+	for b, s := range c.EnforceAfter {
+		if _, ok := defaultSeverity[b]; !ok {
+			return fmt.Errorf("EnforceAfter: unknown bucket %q, want one of %v", b, buckets)
+		}
+		t, err := parseDate(fmt.Sprintf("EnforceAfter[%s]", b), s)
+		if err != nil {
+			return err
+		}
+		c.enforceAfter[b] = t
+	}
+	for i := range c.Forbidden {
+		l := &c.Forbidden[i]
+		t, err := parseDate(l.id("Forbidden", i), l.EnforceAfter)
+		if err != nil {
+			return err
+		}
+		l.enforceAfter = t
+	}
+	for i := range c.DistFiles {
+		l := &c.DistFiles[i].License
+		t, err := parseDate(l.id("DistFiles", i), l.EnforceAfter)
+		if err != nil {
+			return err
+		}
+		l.enforceAfter = t
+	}
+	for i := range c.DirRules {
+		r := &c.DirRules[i]
+		t, err := parseDate(fmt.Sprintf("DirRules[%d]", i), r.EnforceAfter)
+		if err != nil {
+			return err
+		}
+		r.enforceAfter = t
+	}
+	return nil
+}
+
+// enforced reports whether v fails the run at time now, rather than being a
+// warning during the grace period before the rule it breaks takes effect.
+func (c *Config) enforced(v violation, now time.Time) bool {
+	return !now.Before(c.enforceAfter[v.bucket]) && !now.Before(v.enforceAfter)
+}
+
+// reportSeverity returns the severity of v in reports at time now: errors
+// are downgraded to warnings until their rule is enforced.
+func (c *Config) reportSeverity(v violation, now time.Time) string {
+	s := c.severity(v.bucket)
+	if s == severityError && !c.enforced(v, now) {
+		return severityWarning
+	}
+	return s
+}
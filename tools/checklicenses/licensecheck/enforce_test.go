@@ -0,0 +1,56 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEnforceAfter(t *testing.T) {
+	c := &Config{
+		Forbidden:    []License{{Name: "gpl", Lines: []string{"GPL"}, EnforceAfter: "2025-01-01"}},
+		EnforceAfter: map[bucket]string{bucketFormatting: "2030-06-01"},
+	}
+	if err := c.CompileRegexps(); err != nil {
+		t.Fatal(err)
+	}
+
+	before := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	after := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	forbidden := violation{file: "a.go", bucket: bucketForbidden, license: "gpl", enforceAfter: c.Forbidden[0].enforceAfter}
+	formatting := violation{file: "a.go", bucket: bucketFormatting}
+	missing := violation{file: "a.go", bucket: bucketMissing}
+	for _, tt := range []struct {
+		v    violation
+		now  time.Time
+		want bool
+	}{
+		{forbidden, before, false},
+		{forbidden, after, true},
+		{formatting, after, false},
+		{missing, before, true},
+	} {
+		if got := c.enforced(tt.v, tt.now); got != tt.want {
+			t.Errorf("enforced(%+v, %v) = %t, want %t", tt.v, tt.now, got, tt.want)
+		}
+	}
+	if got := c.reportSeverity(forbidden, before); got != severityWarning {
+		t.Errorf("reportSeverity() before EnforceAfter = %q, want %q", got, severityWarning)
+	}
+	if got := c.reportSeverity(forbidden, after); got != severityError {
+		t.Errorf("reportSeverity() after EnforceAfter = %q, want %q", got, severityError)
+	}
+
+	for _, bad := range []*Config{
+		{EnforceAfter: map[bucket]string{bucketMissing: "01/02/2025"}},
+		{EnforceAfter: map[bucket]string{"bogus": "2025-01-01"}},
+		{DirRules: []DirRule{{DirGlob: "*", EnforceAfter: "soon"}}},
+	} {
+		if err := bad.CompileRegexps(); err == nil {
+			t.Errorf("CompileRegexps(%+v) succeeded, want error", bad)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// explain describes, in the order they are applied, how the loaded rules
+// select files and decide whether they conform.
+func (c *Config) explain(w io.Writer) {
+	step := 0
+	p := func(format string, args ...interface{}) {
+		step++
+		fmt.Fprintf(w, "%d. "+format+"\n", append([]interface{}{step}, args...)...)
+	}
+	item := func(format string, args ...interface{}) {
+		fmt.Fprintf(w, "   - "+format+"\n", args...)
+	}
+
+	fmt.Fprintln(w, "Selecting files:")
+	p("List the files added to git. Files in a directory holding a %s are governed by that file instead of this configuration.", dirConfigName)
+	if len(c.goPkg) > 0 {
+		p("Trim the first matching GoPkg prefix from each path; the rules below see the trimmed path.")
+		for _, g := range c.goPkg {
+			item("%q", g)
+		}
+	}
+	if len(c.NoLicenseDirs) > 0 {
+		p("Skip files in any of NoLicenseDirs, before any other rule.")
+		for _, d := range c.NoLicenseDirs {
+			item("%s/", d)
+		}
+	}
+	p("Include a file if any of these accept it; if none do, it is not checked.")
+	for _, e := range c.AcceptExtensions {
+		item("extension %s (AcceptExtensions)", e)
+	}
+	for i, r := range c.accept {
+		item("path matches %s (Accept[%d])", r, i)
+	}
+	if len(c.AcceptExtensions)+len(c.accept) == 0 {
+		item("nothing: no Accept rules are configured, so no file is checked")
+	}
+	if len(c.RejectExtensions)+len(c.reject)+len(c.ignore) > 0 {
+		p("Exclude an included file if any of these reject it, whatever accepted it.")
+		for _, e := range c.RejectExtensions {
+			item("extension %s (RejectExtensions)", e)
+		}
+		for i, r := range c.reject {
+			item("path matches %s (Reject[%d])", r, i)
+		}
+		if len(c.ignore) > 0 {
+			item("the last matching Ignore pattern ignores it, or a directory holding it is ignored")
+		}
+	}
+	if len(c.RejectContent) > 0 {
+		p("Skip a selected file if its first %d KiB match any of RejectContent, before any license check.", contentHeadSize>>10)
+		for _, r := range c.RejectContent {
+			item("%s", r)
+		}
+	}
+	if len(c.Vendor) > 0 {
+		p("Check files in these Vendor directories only for carrying some well-known license.")
+		for _, g := range c.Vendor {
+			item("%s", g)
+		}
+	}
+	if fm := c.FrontMatter; fm != nil {
+		how := "report them as missing"
+		if fm.Fallback {
+			how = "check them like other files"
+		}
+		p("Check files matching any of %q for a license key in their YAML front matter accepted by FrontMatter; if they have none, %s.", fm.Globs, how)
+	}
+	if len(c.AssetDirs) > 0 {
+		p("Check files in these AssetDirs against their own licenses and comment syntax; the first matching one applies.")
+		for _, a := range c.AssetDirs {
+			var how []string
+			if len(a.Licenses) > 0 {
+				how = append(how, "own Licenses")
+			}
+			if a.CommentStyle != "" {
+				how = append(how, a.CommentStyle+" comments")
+			}
+			if len(how) == 0 {
+				how = append(how, "no changes")
+			}
+			item("%s (%s)", a.DirGlob, strings.Join(how, ", "))
+		}
+	}
+
+	step = 0
+	fmt.Fprintln(w, "Checking each file:")
+	p("Pass files generated by a tool, which carry a \"// Code generated ... DO NOT EDIT.\" line.")
+	if len(c.Forbidden) > 0 {
+		p("Report the file as forbidden if it carries any of the Forbidden licenses.")
+		for i := range c.Forbidden {
+			item("%s", c.Forbidden[i].id("Forbidden", i))
+		}
+	}
+	switch {
+	case c.HeaderLines != 0 && c.HeaderBytes != 0:
+		p("Only match headers within the first %d lines and %d bytes of the file.", c.HeaderLines, c.HeaderBytes)
+	case c.HeaderLines != 0:
+		p("Only match headers within the first %d lines of the file.", c.HeaderLines)
+	case c.HeaderBytes != 0:
+		p("Only match headers within the first %d bytes of the file.", c.HeaderBytes)
+	}
+	switch {
+	case c.HeaderEnd == headerEndBlankLine:
+		p("End the header at its first blank line.")
+	case c.headerEndLines > 0:
+		p("End the header after the first %d lines of the file.", c.headerEndLines)
+	}
+	if len(c.VolatilePatterns) > 0 {
+		p("Remove the text matching any of VolatilePatterns %q from the header before matching.", c.VolatilePatterns)
+	}
+	var how []string
+	if c.StripComments {
+		how = append(how, "with comment markers stripped")
+	}
+	if c.UnwrapHeaders {
+		how = append(how, "with soft-wrapped lines joined")
+	}
+	if c.IgnoreYears {
+		how = append(how, "with any copyright years")
+	}
+	desc := ""
+	if len(how) > 0 {
+		desc = " (also " + strings.Join(how, ", ") + ")"
+	}
+	switch c.SPDXMode {
+	case spdxModeOnly:
+		p("Report the file as missing unless it carries an SPDX-License-Identifier tag; Licenses are not matched.")
+	case spdxModeEither:
+		p("Report the file as missing unless it carries an SPDX-License-Identifier tag or one of Licenses%s; the first to match is its license.", desc)
+	default:
+		p("Report the file as missing unless it carries one of Licenses%s; the first to match is its license. Go files carrying one after their package clause are reported as misplaced-header instead.", desc)
+	}
+	if c.SPDXMode == spdxModeBoth {
+		p("Report the file as spdx-tag unless it also carries an SPDX-License-Identifier tag.")
+	}
+	if len(c.SPDXAllowed) > 0 {
+		p("Report the file as spdx-tag if its SPDX-License-Identifier tag names a license outside SPDXAllowed %q.", c.SPDXAllowed)
+	}
+	for i := range c.Licenses {
+		l := &c.Licenses[i]
+		desc := l.id("Licenses", i)
+		if l.Version != "" {
+			desc += " version " + l.Version
+		}
+		if l.Require != "" {
+			desc += fmt.Sprintf(", whose header must also contain %q", l.Require)
+		}
+		if l.MinLines > 0 {
+			desc += fmt.Sprintf(", whose header must span at least %d lines", l.MinLines)
+		}
+		if len(l.MustNotContain) > 0 {
+			desc += fmt.Sprintf(", forbidden if its header matches any of %q", l.MustNotContain)
+		}
+		item("%s", desc)
+	}
+	p("Report the file if the SPDX tag of its license contradicts it, or if it stacks several license headers.")
+	if c.Owner != "" {
+		var fold []string
+		if c.FoldHolderCase {
+			fold = append(fold, "case")
+		}
+		if c.StripHolderAccents {
+			fold = append(fold, "diacritics")
+		}
+		desc := ""
+		if len(fold) > 0 {
+			desc = ", regardless of " + strings.Join(fold, " and ")
+		}
+		p("Report the file unless its copyright holder is %q%s.", c.Owner, desc)
+	}
+	switch {
+	case c.MinYear != 0 && c.MaxYear != 0:
+		p("Report the file if it claims a copyright year before %d or after %d.", c.MinYear, c.MaxYear)
+	case c.MinYear != 0:
+		p("Report the file if it claims a copyright year before %d.", c.MinYear)
+	case c.MaxYear != 0:
+		p("Report the file if it claims a copyright year after %d.", c.MaxYear)
+	}
+	if c.GitYears {
+		p("Report the file as stale-year if its copyright years do not span the years of its first and last git commits.")
+	}
+	if c.BlankLineAfterHeader {
+		p("Report the file unless exactly one blank line follows the header.")
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	c := &Config{
+		Licenses:      []License{{Name: "bsd", Lines: []string{"BSD"}}},
+		Accept:        []string{".*\\.go"},
+		Reject:        []string{"vendor/.*"},
+		NoLicenseDirs: []string{"testdata"},
+		Owner:         "the u-root Authors",
+		GitYears:      true,
+	}
+	if err := c.CompileRegexps(); err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	c.explain(&b)
+	got := b.String()
+	// The rules must be explained in the order they are applied.
+	last := -1
+	for _, want := range []string{
+		"NoLicenseDirs",
+		"testdata/",
+		`^.*\.go$ (Accept[0])`,
+		`^vendor/.*$ (Reject[0])`,
+		"- bsd",
+		`"the u-root Authors"`,
+		"last git commit",
+	} {
+		i := strings.Index(got, want)
+		if i < 0 {
+			t.Errorf("explain() does not mention %q:\n%s", want, got)
+			continue
+		}
+		if i < last {
+			t.Errorf("explain() mentions %q out of order:\n%s", want, got)
+		}
+		last = i
+	}
+}
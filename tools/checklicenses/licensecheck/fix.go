@@ -0,0 +1,194 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// defaultTemplate is the name of the header template used by -fix, relative
+// to the root of the repository.
+const defaultTemplate = ".license-header.txt"
+
+// loadTemplate reads the literal license header inserted by -fix. The
+// template is plain text without comment markers; {{year}} and {{holder}}
+// are replaced by year and holder. An empty name selects defaultTemplate at
+// the top of the git repository.
+func loadTemplate(ctx context.Context, name, holder string, year int) ([]string, error) {
+	if name == "" {
+		out, err := exec.CommandContext(ctx, "git", "rev-parse", "--show-toplevel").Output()
+		if err != nil {
+			return nil, fmt.Errorf("-fix needs a header template: pass -template or run inside a git repository with a %s", defaultTemplate)
+		}
+		name = filepath.Join(strings.TrimSpace(string(out)), defaultTemplate)
+	}
+	buf, err := os.ReadFile(name)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("-fix needs a header template, but %s does not exist; create it or pass -template", name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header template: %v", err)
+	}
+	return expandTemplate(name, string(buf), holder, year)
+}
+
+// expandTemplate returns the lines of text, the header template called name,
+// with {{year}} and {{holder}} replaced by year and holder.
+func expandTemplate(name, text, holder string, year int) ([]string, error) {
+	if strings.Contains(text, "{{holder}}") && holder == "" {
+		return nil, fmt.Errorf("header template %s refers to {{holder}}, pass -holder", name)
+	}
+	text = strings.ReplaceAll(text, "{{year}}", strconv.Itoa(year))
+	text = strings.ReplaceAll(text, "{{holder}}", holder)
+	return strings.Split(strings.TrimRight(text, "\n"), "\n"), nil
+}
+
+// headerTemplates are the headers -fix inserts into files without one.
+type headerTemplates struct {
+	// byKey holds the expanded HeaderTemplates, by templateKey.
+	byKey map[string][]string
+	// header is inserted into the other files. If it could not be
+	// loaded, err says why.
+	header []string
+	err    error
+}
+
+// templateKey returns the key of file in HeaderTemplates: its extension, or
+// its name if it has none.
+func templateKey(file string) string {
+	if ext := path.Ext(file); ext != "" {
+		return ext
+	}
+	return path.Base(file)
+}
+
+// loadTemplates returns the headers -fix inserts: the HeaderTemplates of
+// config, and the -template header called name for the other files. The
+// -template header is only required if there are no HeaderTemplates.
+func loadTemplates(ctx context.Context, config *Config, name, holder string, year int) (*headerTemplates, error) {
+	t := &headerTemplates{byKey: map[string][]string{}}
+	for key, lines := range config.HeaderTemplates {
+		header, err := expandTemplate("HeaderTemplates["+strconv.Quote(key)+"]", strings.Join(lines, "\n"), holder, year)
+		if err != nil {
+			return nil, err
+		}
+		t.byKey[key] = header
+	}
+	t.header, t.err = loadTemplate(ctx, name, holder, year)
+	if t.err != nil && (len(t.byKey) == 0 || name != "") {
+		return nil, t.err
+	}
+	return t, nil
+}
+
+// forFile returns the header to insert into file.
+func (t *headerTemplates) forFile(file string) ([]string, error) {
+	if t == nil {
+		return nil, fmt.Errorf("cannot fix %s: no header template", file)
+	}
+	if header, ok := t.byKey[templateKey(file)]; ok {
+		return header, nil
+	}
+	if t.err != nil {
+		return nil, fmt.Errorf("cannot fix %s: no HeaderTemplates entry for %s, and %v", file, templateKey(file), t.err)
+	}
+	return t.header, nil
+}
+
+// insertHeader returns contents with header, commented in the syntax of
+// file, inserted at the top. A leading shebang line stays first.
+func (c *Config) insertHeader(file string, contents []byte, header []string) ([]byte, error) {
+	style, ok := c.styleFor(file, contents)
+	if !ok {
+		return nil, fmt.Errorf("cannot fix %s: unknown comment syntax", file)
+	}
+	var b bytes.Buffer
+	if style == markupStyle {
+		// The prolog must stay first.
+		if i := markupProlog(contents); i > 0 {
+			b.Write(contents[:i])
+			b.WriteByte('\n')
+			contents = bytes.TrimLeft(contents[i:], "\r\n")
+		}
+	}
+	if bytes.HasPrefix(contents, []byte("#!")) {
+		i := bytes.IndexByte(contents, '\n')
+		if i < 0 {
+			i = len(contents)
+			contents = append(contents, '\n')
+		}
+		b.Write(contents[:i+1])
+		contents = contents[i+1:]
+	}
+	b.Write(style.comment(header))
+	b.WriteByte('\n')
+	b.Write(contents)
+	return b.Bytes(), nil
+}
+
+// fixBlankLines returns contents with exactly one blank line between the
+// license header and the code.
+func (c *Config) fixBlankLines(file string, contents []byte) ([]byte, error) {
+	style, ok := c.styleFor(file, contents)
+	if !ok {
+		return nil, fmt.Errorf("cannot fix %s: unknown comment syntax", file)
+	}
+	end, ok := style.headerEnd(contents)
+	if !ok {
+		return nil, fmt.Errorf("cannot fix %s: no header found", file)
+	}
+	rest := contents[end:]
+	for n := blankLines(rest); n > 0; n-- {
+		rest = rest[bytes.IndexByte(rest, '\n')+1:]
+	}
+	var b bytes.Buffer
+	b.Write(contents[:end])
+	b.WriteByte('\n')
+	b.Write(rest)
+	return b.Bytes(), nil
+}
+
+// fixFile fixes the violation v in place, in the comment syntax of config,
+// the configuration governing the file. templates hold the license header to
+// insert into files without one. With dryRun, the file is left unchanged, and
+// only the error fixing it would have returned is.
+func fixFile(v violation, config *Config, templates *headerTemplates, dryRun bool) error {
+	info, err := os.Stat(v.file)
+	if err != nil {
+		return err
+	}
+	contents, err := os.ReadFile(v.file)
+	if err != nil {
+		return err
+	}
+	var fixed []byte
+	switch v.bucket {
+	case bucketMissing:
+		var header []string
+		if header, err = templates.forFile(v.file); err == nil {
+			fixed, err = config.insertHeader(v.file, contents, header)
+		}
+	case bucketFormatting:
+		fixed, err = config.fixBlankLines(v.file, contents)
+	case bucketCommentStyle:
+		fixed, err = config.fixCommentForm(v.file, contents)
+	case bucketDuplicate:
+		fixed, err = config.collapseHeaders(v.file, contents)
+	default:
+		return fmt.Errorf("cannot fix %s: %s violations need manual attention", v.file, v.bucket)
+	}
+	if err != nil || dryRun {
+		return err
+	}
+	return os.WriteFile(v.file, fixed, info.Mode().Perm())
+}
@@ -0,0 +1,213 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadTemplate(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "header.txt")
+	if err := os.WriteFile(name, []byte("Copyright {{year}} {{holder}}. All rights reserved\n\nSee LICENSE.\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := loadTemplate(context.Background(), name, "the u-root Authors", 2022)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"Copyright 2022 the u-root Authors. All rights reserved", "", "See LICENSE."}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("loadTemplate() = %q, want %q", got, want)
+	}
+	if _, err := loadTemplate(context.Background(), name, "", 2022); err == nil {
+		t.Error("loadTemplate() without holder succeeded, want error")
+	}
+	if _, err := loadTemplate(context.Background(), filepath.Join(t.TempDir(), "missing"), "", 2022); err == nil {
+		t.Error("loadTemplate() of missing template succeeded, want error")
+	}
+}
+
+func TestLoadTemplates(t *testing.T) {
+	c := &Config{HeaderTemplates: map[string][]string{
+		".sh":      {"Copyright {{year}} {{holder}}", "", "SPDX-License-Identifier: MIT"},
+		"Makefile": {"Copyright {{year}} Make"},
+	}}
+	if err := c.CompileRegexps(); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	missing := filepath.Join(t.TempDir(), "missing")
+	// Without a -template, the HeaderTemplates are enough.
+	templates, err := loadTemplates(ctx, c, "", "X", 2026)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for file, want := range map[string][]string{
+		"tools/run.sh":   {"Copyright 2026 X", "", "SPDX-License-Identifier: MIT"},
+		"tools/Makefile": {"Copyright 2026 Make"},
+	} {
+		if got, err := templates.forFile(file); err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("forFile(%s) = %q, %v, want %q", file, got, err, want)
+		}
+	}
+	if _, err := templates.forFile("a.bin"); err == nil && templates.err != nil {
+		t.Error("forFile(a.bin) without a template succeeded, want error")
+	}
+	if _, err := loadTemplates(ctx, c, missing, "X", 2026); err == nil {
+		t.Error("loadTemplates() with a missing -template succeeded, want error")
+	}
+	if _, err := loadTemplates(ctx, c, missing, "", 2026); err == nil {
+		t.Error("loadTemplates() without holder succeeded, want error")
+	}
+	if _, err := loadTemplates(ctx, &Config{}, missing, "X", 2026); err == nil {
+		t.Error("loadTemplates() without any template succeeded, want error")
+	}
+}
+
+func TestInsertHeader(t *testing.T) {
+	header := []string{"Copyright 2022 X", "", "BSD"}
+	for _, tt := range []struct {
+		file string
+		in   string
+		want string
+	}{
+		{
+			file: "a.go",
+			in:   "package a\n",
+			want: "// Copyright 2022 X\n//\n// BSD\n\npackage a\n",
+		},
+		{
+			file: "run.sh",
+			in:   "#!/bin/sh\necho hi\n",
+			want: "#!/bin/sh\n# Copyright 2022 X\n#\n# BSD\n\necho hi\n",
+		},
+		{
+			file: "run.py",
+			in:   "#!/usr/bin/env python",
+			want: "#!/usr/bin/env python\n# Copyright 2022 X\n#\n# BSD\n\n",
+		},
+		{
+			file: "tools/build",
+			in:   "#!/bin/bash\necho hi\n",
+			want: "#!/bin/bash\n# Copyright 2022 X\n#\n# BSD\n\necho hi\n",
+		},
+		{
+			file: "a_linux.go",
+			in:   "//go:build linux\n\npackage a\n",
+			want: "// Copyright 2022 X\n//\n// BSD\n\n//go:build linux\n\npackage a\n",
+		},
+		{
+			file: "a.svg",
+			in:   "<?xml version=\"1.0\"?>\n<svg/>\n",
+			want: "<?xml version=\"1.0\"?>\n<!--\nCopyright 2022 X\n\nBSD\n-->\n\n<svg/>\n",
+		},
+	} {
+		got, err := (&Config{}).insertHeader(tt.file, []byte(tt.in), header)
+		if err != nil {
+			t.Errorf("insertHeader(%s) = %v", tt.file, err)
+			continue
+		}
+		if string(got) != tt.want {
+			t.Errorf("insertHeader(%s) = %q, want %q", tt.file, got, tt.want)
+		}
+	}
+	if _, err := (&Config{}).insertHeader("data.bin", nil, header); err == nil {
+		t.Error("insertHeader(data.bin) succeeded, want error")
+	}
+}
+
+func TestBlankLineAfterHeader(t *testing.T) {
+	c := &Config{Licenses: []License{{Lines: []string{"^// Copyright"}}}, BlankLineAfterHeader: true}
+	if err := c.CompileRegexps(); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		in   string
+		want bucket
+		fix  string
+	}{
+		{in: "// Copyright X\n// BSD\n\npackage a\n"},
+		{in: "// Copyright X\n\n//go:build linux\n\npackage a\n"},
+		{
+			in:   "// Copyright X\n// BSD\npackage a\n",
+			want: bucketFormatting,
+			fix:  "// Copyright X\n// BSD\n\npackage a\n",
+		},
+		{
+			in:   "// Copyright X\n\n\n \npackage a\n",
+			want: bucketFormatting,
+			fix:  "// Copyright X\n\npackage a\n",
+		},
+	} {
+		if got := c.checkContents("a.go", []byte(tt.in)); got != tt.want {
+			t.Errorf("checkContents(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+		if tt.want == "" {
+			continue
+		}
+		got, err := c.fixBlankLines("a.go", []byte(tt.in))
+		if err != nil {
+			t.Errorf("fixBlankLines(%q) = %v", tt.in, err)
+			continue
+		}
+		if string(got) != tt.fix {
+			t.Errorf("fixBlankLines(%q) = %q, want %q", tt.in, got, tt.fix)
+		}
+	}
+}
+
+func TestFixFileDryRun(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "a.go")
+	in := "// Copyright X\n// BSD\npackage a\n"
+	if err := os.WriteFile(file, []byte(in), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	v := violation{file: file, bucket: bucketFormatting}
+	for _, tt := range []struct {
+		dryRun bool
+		want   string
+	}{
+		{true, in},
+		{false, "// Copyright X\n// BSD\n\npackage a\n"},
+	} {
+		if err := fixFile(v, &Config{}, nil, tt.dryRun); err != nil {
+			t.Fatalf("fixFile(%t) = %v", tt.dryRun, err)
+		}
+		got, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("fixFile(%t) left %q, want %q", tt.dryRun, got, tt.want)
+		}
+	}
+	if err := fixFile(violation{file: file, bucket: bucketForbidden}, &Config{}, nil, true); err == nil {
+		t.Error("fixFile(forbidden) succeeded, want error")
+	}
+}
+
+func TestFixAssetDir(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"config.json":           `{"licenses": [["^// Copyright \\d+ X"]], "accept": [".*\\.html"], "stripcomments": true, "assetdirs": [{"dirglob": "templates", "licenses": [["^Copyright \\d+ X"]], "commentstyle": "template"}]}`,
+		"header.txt":            "Copyright 2026 X\n",
+		"tree/templates/a.html": "<p>hi</p>\n",
+	})
+	args := []string{"-c", filepath.Join(dir, "config.json"), "-walk", filepath.Join(dir, "tree"), "-fix", "-template", filepath.Join(dir, "header.txt")}
+	if code, _, stderr := runFlags(args...); code != exitOK {
+		t.Fatalf("-fix = %d, want %d; stderr:\n%s", code, exitOK, stderr)
+	}
+	got, err := os.ReadFile(filepath.Join(dir, "tree/templates/a.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "{{/*\nCopyright 2026 X\n*/}}\n\n<p>hi</p>\n"; string(got) != want {
+		t.Errorf("-fix wrote %q, want %q in the comment syntax of the AssetDir", got, want)
+	}
+}
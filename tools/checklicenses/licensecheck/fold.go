@@ -0,0 +1,31 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// foldHolder returns the copyright holder h as it is compared with Owner:
+// normalized and lower cased with FoldHolderCase, and without diacritics, so
+// that "Müller" reads "Muller", with StripHolderAccents.
+func (c *Config) foldHolder(h string) string {
+	if c.StripHolderAccents {
+		var b strings.Builder
+		for _, r := range norm.NFD.String(h) {
+			if !unicode.Is(unicode.Mn, r) {
+				b.WriteRune(r)
+			}
+		}
+		h = norm.NFC.String(b.String())
+	}
+	if c.FoldHolderCase {
+		h = strings.ToLower(norm.NFC.String(h))
+	}
+	return h
+}
@@ -0,0 +1,126 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// FrontMatter checks documents, typically Markdown, which declare their
+// license in a "license:" key of YAML front matter, the block delimited by
+// "---" lines at their top.
+type FrontMatter struct {
+	// Globs match the documents, relative to GoPkg, e.g. "docs/*.md".
+	Globs []string
+	// Licenses are the accepted values of the license key, compared
+	// case-insensitively. If empty, the SPDX ids of Licenses are.
+	Licenses []string `json:",omitempty"`
+	// Fallback checks documents without front matter like other files,
+	// for a license in their leading HTML comment. Otherwise they are
+	// reported as missing.
+	Fallback bool `json:",omitempty"`
+	allowed  map[string]bool
+}
+
+// compileFrontMatter checks the globs of FrontMatter and gathers the
+// licenses it accepts.
+func (c *Config) compileFrontMatter() error {
+	fm := c.FrontMatter
+	if fm == nil {
+		return nil
+	}
+	for i, g := range fm.Globs {
+		if _, err := path.Match(g, ""); err != nil {
+			return fmt.Errorf("FrontMatter.Globs[%d]: invalid glob %q: %v", i, g, err)
+		}
+	}
+	allowed := fm.Licenses
+	if len(allowed) == 0 {
+		for _, l := range c.Licenses {
+			if l.SPDX != "" {
+				allowed = append(allowed, l.SPDX)
+			}
+		}
+	}
+	if len(allowed) == 0 {
+		return fmt.Errorf("FrontMatter accepts no license: list its Licenses, or give Licenses an SPDX id")
+	}
+	fm.allowed = make(map[string]bool, len(allowed))
+	for _, l := range allowed {
+		fm.allowed[strings.ToLower(l)] = true
+	}
+	return nil
+}
+
+// frontMatterDoc reports whether FrontMatter governs file, a path relative to
+// GoPkg.
+func (c *Config) frontMatterDoc(file string) bool {
+	if c.FrontMatter == nil {
+		return false
+	}
+	for _, g := range c.FrontMatter.Globs {
+		if ok, _ := path.Match(g, file); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// frontMatter returns the lines of the front matter at the top of contents,
+// without its delimiters, and false if there is none.
+func frontMatter(contents []byte) ([]string, bool) {
+	contents = bytes.TrimPrefix(contents, []byte("\xef\xbb\xbf"))
+	lines := strings.Split(string(contents), "\n")
+	if strings.TrimRight(lines[0], " \t\r") != "---" {
+		return nil, false
+	}
+	for i, l := range lines[1:] {
+		// YAML ends a document with "---" or "...".
+		if l = strings.TrimRight(l, " \t\r"); l == "---" || l == "..." {
+			return lines[1 : i+1], true
+		}
+	}
+	return nil, false
+}
+
+// frontMatterLicense returns the value of the top-level license key of the
+// front matter, unquoted, and false if there is none.
+func frontMatterLicense(lines []string) (string, bool) {
+	for _, l := range lines {
+		if !strings.HasPrefix(l, "license:") {
+			continue
+		}
+		v := strings.TrimSpace(strings.TrimPrefix(l, "license:"))
+		if i := strings.Index(v, " #"); i >= 0 {
+			v = strings.TrimSpace(v[:i])
+		}
+		if len(v) >= 2 && (v[0] == '"' || v[0] == '\'') && v[len(v)-1] == v[0] {
+			v = v[1 : len(v)-1]
+		}
+		return v, v != ""
+	}
+	return "", false
+}
+
+// checkFrontMatter returns the bucket of the violation in a document
+// governed by FrontMatter, or "" if it declares an accepted license. It
+// returns false if the document has no front matter and should be checked
+// like other files.
+func (c *Config) checkFrontMatter(contents []byte) (bucket, bool) {
+	lines, ok := frontMatter(contents)
+	if !ok {
+		if c.FrontMatter.Fallback {
+			return "", false
+		}
+		return bucketMissing, true
+	}
+	if l, ok := frontMatterLicense(lines); ok && c.FrontMatter.allowed[strings.ToLower(l)] {
+		return "", true
+	}
+	return bucketMissing, true
+}
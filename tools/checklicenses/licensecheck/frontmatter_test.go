@@ -0,0 +1,56 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import "testing"
+
+func TestFrontMatter(t *testing.T) {
+	for _, fallback := range []bool{false, true} {
+		c := &Config{
+			Licenses:    []License{{Lines: []string{"SPDX-License-Identifier: CC-BY-4.0"}, SPDX: "CC-BY-4.0"}},
+			FrontMatter: &FrontMatter{Globs: []string{"docs/*.md"}, Fallback: fallback},
+		}
+		if err := c.CompileRegexps(); err != nil {
+			t.Fatal(err)
+		}
+		for _, tt := range []struct {
+			in   string
+			want bucket
+		}{
+			{"---\ntitle: Boot\nlicense: CC-BY-4.0\n---\n# Boot\n", ""},
+			{"---\nlicense: \"cc-by-4.0\" # docs\n...\n# Boot\n", ""},
+			{"---\nlicense: MIT\n---\n# Boot\n", bucketMissing},
+			{"---\ntitle: Boot\n---\n# Boot\n", bucketMissing},
+			{"---\nauthors:\n  license: CC-BY-4.0\n---\n", bucketMissing},
+			{"---\nlicense: CC-BY-4.0\n# never closed\n", bucketMissing},
+			{"<!-- SPDX-License-Identifier: CC-BY-4.0 -->\n# Boot\n", map[bool]bucket{false: bucketMissing, true: ""}[fallback]},
+		} {
+			cd := candidate{file: "docs/boot.md", config: c, frontMatter: true}
+			if got := cd.classify(cd.file, []byte(tt.in)).bucket; got != tt.want {
+				t.Errorf("Fallback %t: classify(%q) = %q, want %q", fallback, tt.in, got, tt.want)
+			}
+		}
+	}
+}
+
+func TestFrontMatterConfig(t *testing.T) {
+	c := &Config{FrontMatter: &FrontMatter{Globs: []string{"docs/*.md"}}}
+	if err := c.CompileRegexps(); err == nil {
+		t.Error("CompileRegexps() with FrontMatter accepting no license succeeded, want error")
+	}
+	c = &Config{FrontMatter: &FrontMatter{Globs: []string{"docs/*.md"}, Licenses: []string{"MIT"}}}
+	if err := c.CompileRegexps(); err != nil {
+		t.Fatal(err)
+	}
+	for file, want := range map[string]bool{
+		"docs/boot.md":     true,
+		"docs/sub/boot.md": false,
+		"README.md":        false,
+	} {
+		if got := c.frontMatterDoc(file); got != want {
+			t.Errorf("frontMatterDoc(%s) = %t, want %t", file, got, want)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright 2017-2018 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// runGit runs git with args and returns its output. stdin, if not empty, is
+// fed to its standard input. Failures are retried up to -git-retries times,
+// waiting -git-backoff before the first retry and twice as long before each
+// next, as transient failures like a contended index.lock are common on
+// loaded CI machines.
+func runGit(ctx context.Context, stdin string, args ...string) ([]byte, error) {
+	wait := *gitBackoff
+	for attempt := 1; ; attempt++ {
+		cmd := exec.CommandContext(ctx, "git", args...)
+		if stdin != "" {
+			cmd.Stdin = strings.NewReader(stdin)
+		}
+		out, err := cmd.Output()
+		if err == nil {
+			return out, nil
+		}
+		var exit *exec.ExitError
+		if errors.As(err, &exit) && len(exit.Stderr) > 0 {
+			err = fmt.Errorf("%v: %s", err, strings.TrimSpace(string(exit.Stderr)))
+		}
+		if attempt > *gitRetries || ctx.Err() != nil || errors.Is(err, exec.ErrNotFound) {
+			if attempt > 1 {
+				return nil, fmt.Errorf("error running git %s, %d attempts failed: %v", args[0], attempt, err)
+			}
+			return nil, fmt.Errorf("error running git %s: %v", args[0], err)
+		}
+		log.Printf("git %s failed, retrying in %v: %v", args[0], wait, err)
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return nil, fmt.Errorf("error running git %s: %v", args[0], ctx.Err())
+		}
+		wait *= 2
+	}
+}
+
+// gitFiles lists the files added to the git repository.
+func gitFiles(ctx context.Context) ([]string, error) {
+	out, err := runGit(ctx, "", "ls-files")
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(out)), nil
+}
+
+// gitUntracked returns the files which are neither tracked nor ignored by
+// .gitignore and friends.
+func gitUntracked(ctx context.Context) ([]string, error) {
+	out, err := runGit(ctx, "", "ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(out)), nil
+}
+
+// gitGenerated returns the subset of files which .gitattributes marks as
+// linguist-generated.
+func gitGenerated(ctx context.Context, files []string) (map[string]bool, error) {
+	out, err := runGit(ctx, strings.Join(files, "\x00"), "check-attr", "-z", "--stdin", "linguist-generated")
+	if err != nil {
+		return nil, err
+	}
+	// The output is a sequence of NUL-terminated <path> <attribute> <info>
+	// triples.
+	fields := strings.Split(string(out), "\x00")
+	gen := map[string]bool{}
+	for i := 0; i+2 < len(fields); i += 3 {
+		if info := fields[i+2]; info == "set" || info == "true" {
+			gen[fields[i]] = true
+		}
+	}
+	return gen, nil
+}
+
+// gitChanged lists the files whose git status relative to base is one of
+// the letters in status, e.g. "A" for added or "ACM" for added, copied or
+// modified.
+func gitChanged(ctx context.Context, base, status string) ([]string, error) {
+	out, err := runGit(ctx, "", "diff", "--relative", "--name-only", "--diff-filter="+status, base, "--")
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(out)), nil
+}
+
+// gitCommitYears returns the years of the first and of the last commit
+// touching each file of the git history, by path relative to the current
+// directory.
+func gitCommitYears(ctx context.Context) (first, last map[string]int, err error) {
+	out, err := runGit(ctx, "", "log", "--format=format:%x00%ad", "--date=format:%Y", "--name-only", "--relative")
+	if err != nil {
+		return nil, nil, err
+	}
+	first, last = map[string]int{}, map[string]int{}
+	// Each commit is a NUL, its year, and the files it touched, one per
+	// line. The log starts with the newest commit, so that older commits
+	// overwrite the first year.
+	for _, commit := range strings.Split(string(out), "\x00")[1:] {
+		lines := strings.Split(commit, "\n")
+		year, err := strconv.Atoi(lines[0])
+		if err != nil {
+			return nil, nil, fmt.Errorf("error running git log: unexpected year %q", lines[0])
+		}
+		for _, f := range lines[1:] {
+			if f == "" {
+				continue
+			}
+			if _, ok := last[f]; !ok {
+				last[f] = year
+			}
+			first[f] = year
+		}
+	}
+	return first, last, nil
+}
+
+// gitFirstAdded returns the commit which first added each file in the
+// history of HEAD, by path relative to the current directory. A file renamed
+// counts as added under its new name.
+func gitFirstAdded(ctx context.Context) (map[string]string, error) {
+	out, err := runGit(ctx, "", "log", "--diff-filter=A", "--no-renames", "--format=format:%x00%H", "--name-only", "--relative")
+	if err != nil {
+		return nil, err
+	}
+	added := map[string]string{}
+	// Each commit is a NUL, its hash, and the files it added, one per
+	// line. The log starts with the newest commit, so that older commits
+	// overwrite newer ones for files added again after a deletion.
+	for _, commit := range strings.Split(string(out), "\x00")[1:] {
+		lines := strings.Split(commit, "\n")
+		for _, f := range lines[1:] {
+			if f != "" {
+				added[f] = lines[0]
+			}
+		}
+	}
+	return added, nil
+}
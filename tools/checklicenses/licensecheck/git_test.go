@@ -0,0 +1,146 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeGit puts a git on the PATH which fails the first failures times it
+// runs, and then prints its arguments.
+func fakeGit(t *testing.T, failures int) {
+	dir := t.TempDir()
+	script := `#!/bin/sh
+n=$(cat "` + dir + `/count" 2>/dev/null || echo 0)
+echo $((n + 1)) > "` + dir + `/count"
+if [ "$n" -lt ` + strconv.Itoa(failures) + ` ]; then
+	echo "fatal: Unable to create '.git/index.lock': File exists." >&2
+	exit 128
+fi
+echo "$@"
+`
+	if err := os.WriteFile(filepath.Join(dir, "git"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestRunGitRetries(t *testing.T) {
+	defer func(r int, b time.Duration) { *gitRetries, *gitBackoff = r, b }(*gitRetries, *gitBackoff)
+	*gitBackoff = time.Millisecond
+	for _, tt := range []struct {
+		retries, failures int
+		err               string
+	}{
+		{retries: 0, failures: 0},
+		{retries: 0, failures: 1, err: "error running git ls-files: exit status 128: fatal: Unable to create"},
+		{retries: 2, failures: 2},
+		{retries: 2, failures: 3, err: "error running git ls-files, 3 attempts failed: exit status 128"},
+	} {
+		fakeGit(t, tt.failures)
+		*gitRetries = tt.retries
+		out, err := runGit(context.Background(), "", "ls-files")
+		switch {
+		case tt.err == "" && err != nil:
+			t.Errorf("%d retries, %d failures: runGit() = %v", tt.retries, tt.failures, err)
+		case tt.err == "" && string(out) != "ls-files\n":
+			t.Errorf("%d retries, %d failures: runGit() = %q, want %q", tt.retries, tt.failures, out, "ls-files\n")
+		case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+			t.Errorf("%d retries, %d failures: runGit() = %v, want error containing %q", tt.retries, tt.failures, err, tt.err)
+		}
+	}
+}
+
+func TestListUntracked(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	for name, contents := range map[string]string{
+		"a.go":       "tracked\n",
+		"c.go":       "tracked\n",
+		"b.go":       "untracked\n",
+		"ignored.go": "ignored\n",
+		".gitignore": "ignored.go\n",
+	} {
+		if err := os.WriteFile(name, []byte(contents), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, args := range [][]string{{"init", "-q"}, {"add", "a.go", "c.go", ".gitignore"}} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	ctx := context.Background()
+	for _, tt := range []struct {
+		untracked bool
+		want      []string
+	}{
+		{untracked: false, want: []string{".gitignore", "a.go", "c.go"}},
+		{untracked: true, want: []string{".gitignore", "a.go", "b.go", "c.go"}},
+	} {
+		got, err := gitLister{untracked: tt.untracked}.ListFiles(ctx)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("untracked %t: ListFiles() = %q, %v, want %q", tt.untracked, got, err, tt.want)
+		}
+	}
+}
+
+func TestGitChangedRange(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name, contents string) {
+		if err := os.WriteFile(name, []byte(contents), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	git("init", "-q")
+	write("a.go", "a\n")
+	write("c.go", "c\n")
+	write("d.go", "d\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "base")
+	write("a.go", "changed\n")
+	write("b.go", "added\n")
+	git("add", ".")
+	git("rm", "-q", "c.go")
+	git("commit", "-q", "-m", "head")
+
+	got, err := gitChanged(context.Background(), "HEAD~1..HEAD", "d")
+	if want := []string{"a.go", "b.go"}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("gitChanged(HEAD~1..HEAD, d) = %q, %v, want %q", got, err, want)
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// policyOptions are the command line options which change the outcome of a
+// run, and hence its configuration hash.
+type policyOptions struct {
+	SkipGenerated bool
+	Decompress    bool
+	Status        string
+	StatusBase    string
+}
+
+// currentPolicyOptions returns the policy options given on the command line.
+func currentPolicyOptions() policyOptions {
+	return policyOptions{
+		SkipGenerated: *skipGen,
+		Decompress:    *decompress,
+		Status:        *status,
+		StatusBase:    *statusBase,
+	}
+}
+
+// hash returns a digest of the loaded configuration and opts, which changes
+// whenever any policy-affecting setting does. Licenses loaded from files
+// contribute their text, so editing a license file changes the hash too.
+func (c *Config) hash(opts policyOptions) (string, error) {
+	b, err := json.Marshal(struct {
+		Config  *Config
+		GoPkg   []string
+		Options policyOptions
+	}{c, c.goPkg, opts})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,34 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"testing"
+)
+
+func TestHash(t *testing.T) {
+	load := func() *Config {
+		c, err := loadConfig("testdata/licenses/config.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	c := load()
+	h1, err := c.hash(policyOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h2, _ := load().hash(policyOptions{}); h1 != h2 {
+		t.Errorf("hash() of the same config = %s and %s, want equal", h1, h2)
+	}
+	if h, _ := c.hash(policyOptions{SkipGenerated: true}); h == h1 {
+		t.Errorf("hash() did not change with the options")
+	}
+	c.Reject = append(c.Reject, "vendor/.*")
+	if h, _ := c.hash(policyOptions{}); h == h1 {
+		t.Errorf("hash() did not change with Reject")
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// The values of HeaderEnd.
+const (
+	headerEndFirstCode = "first-code"
+	headerEndBlankLine = "blank-line"
+	headerEndLines     = "lines:"
+)
+
+// compileHeaderEnd checks HeaderEnd, and parses the line count of
+// "lines:N".
+func (c *Config) compileHeaderEnd() error {
+	c.headerEndLines = 0
+	switch {
+	case c.HeaderEnd == "" || c.HeaderEnd == headerEndFirstCode || c.HeaderEnd == headerEndBlankLine:
+		return nil
+	case strings.HasPrefix(c.HeaderEnd, headerEndLines):
+		n, err := strconv.Atoi(strings.TrimPrefix(c.HeaderEnd, headerEndLines))
+		if err == nil && n > 0 {
+			c.headerEndLines = n
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid HeaderEnd %q, want %s, %s or %sN with N > 0", c.HeaderEnd, headerEndFirstCode, headerEndBlankLine, headerEndLines)
+}
+
+// stripHeader returns the header of contents, written in style, with the
+// comment markers removed, where HeaderEnd says the header ends.
+func (c *Config) stripHeader(style commentStyle, contents []byte) []byte {
+	switch {
+	case c.HeaderEnd == headerEndBlankLine:
+		end, _ := style.headerEnd(contents)
+		contents = contents[:end]
+	case c.headerEndLines > 0:
+		end := 0
+		for n := 0; n < c.headerEndLines && end < len(contents); n++ {
+			i := bytes.IndexByte(contents[end:], '\n')
+			if i < 0 {
+				end = len(contents)
+				break
+			}
+			end += i + 1
+		}
+		contents = contents[:end]
+	}
+	return style.stripHeader(contents)
+}
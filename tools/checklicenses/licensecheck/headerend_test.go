@@ -0,0 +1,46 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import "testing"
+
+func TestHeaderEnd(t *testing.T) {
+	const (
+		doc     = "// Copyright 2026 X\n\n// Package x does y.\npackage x\n"
+		build   = "//go:build linux\n\n// Copyright 2026 X\npackage x\n"
+		shebang = "#!/bin/sh\n# Copyright 2026 X\n# BSD\necho\n"
+	)
+	for _, tt := range []struct {
+		end      string
+		file     string
+		contents string
+		want     string
+	}{
+		{end: "", file: "a.go", contents: doc, want: "Copyright 2026 X\nPackage x does y.\n"},
+		{end: "first-code", file: "a.go", contents: doc, want: "Copyright 2026 X\nPackage x does y.\n"},
+		{end: "blank-line", file: "a.go", contents: doc, want: "Copyright 2026 X\n"},
+		{end: "blank-line", file: "a.go", contents: build, want: "go:build linux\n"},
+		{end: "first-code", file: "a.go", contents: build, want: "go:build linux\nCopyright 2026 X\n"},
+		{end: "lines:1", file: "a.go", contents: doc, want: "Copyright 2026 X\n"},
+		{end: "lines:9", file: "a.go", contents: doc, want: "Copyright 2026 X\nPackage x does y.\n"},
+		{end: "lines:2", file: "build", contents: shebang, want: "Copyright 2026 X\n"},
+		{end: "blank-line", file: "build", contents: shebang, want: "Copyright 2026 X\nBSD\n"},
+	} {
+		c := &Config{HeaderEnd: tt.end}
+		if err := c.CompileRegexps(); err != nil {
+			t.Fatal(err)
+		}
+		style, _ := styleFor(tt.file, []byte(tt.contents))
+		if got := string(c.stripHeader(style, []byte(tt.contents))); got != tt.want {
+			t.Errorf("HeaderEnd %q: stripHeader(%q) = %q, want %q", tt.end, tt.contents, got, tt.want)
+		}
+	}
+	for _, end := range []string{"first", "lines:", "lines:0", "lines:x"} {
+		c := &Config{HeaderEnd: end}
+		if err := c.CompileRegexps(); err == nil {
+			t.Errorf("HeaderEnd %q: CompileRegexps() = nil, want error", end)
+		}
+	}
+}
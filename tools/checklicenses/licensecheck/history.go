@@ -0,0 +1,39 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"errors"
+	"fmt"
+)
+
+// shortHash is the length of the commit hashes in reports.
+const shortHash = 12
+
+// checkAdded returns v, the violation in the current contents of the
+// candidate, or an added-unlicensed violation if the contents the candidate
+// was added with carried no license.
+func (cd candidate) checkAdded(v violation) (violation, error) {
+	contents, err := cd.history.readAt(cd.addedIn+":", cd.file)
+	if errors.Is(err, errNoBlob) {
+		return v, nil
+	}
+	if err != nil || contents == nil {
+		return v, err
+	}
+	name, contents, err := cd.opts.decode(cd.file, contents)
+	if err != nil {
+		return v, err
+	}
+	switch cd.config.checkContents(name, contents) {
+	case bucketMissing, bucketMisplaced:
+		commit := cd.addedIn
+		if len(commit) > shortHash {
+			commit = commit[:shortHash]
+		}
+		v.bucket, v.detail = bucketAddedUnlicensed, fmt.Sprintf("added in %s without a license", commit)
+	}
+	return v, nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestCheckAdded(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name, contents string) {
+		if err := os.WriteFile(name, []byte(contents), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	const licensed = "// Copyright 2026 X\npackage a\n"
+	git("init", "-q")
+	write("inception.go", licensed)
+	write("fixed.go", "package a\n")
+	write("moved.go", "package a\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "add")
+	write("fixed.go", licensed)
+	write("moved.go", licensed)
+	git("add", ".")
+	git("commit", "-q", "-m", "fix")
+	git("mv", "moved.go", "renamed.go")
+	git("commit", "-q", "-m", "rename")
+	write("new.go", licensed)
+
+	added, err := gitFirstAdded(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	history, err := newBlobReader(context.Background(), "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer history.Close()
+	c := &Config{Licenses: []License{{Lines: []string{"^// Copyright"}}}}
+	if err := c.CompileRegexps(); err != nil {
+		t.Fatal(err)
+	}
+	for file, want := range map[string]bucket{
+		"inception.go": "",
+		"fixed.go":     bucketAddedUnlicensed,
+		// Renamed files count as added when renamed.
+		"renamed.go": "",
+		// Uncommitted files have no history to check.
+		"new.go": "",
+	} {
+		v, err := candidate{file: file, config: c, addedIn: added[file], history: history}.check()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v.bucket != want {
+			t.Errorf("check(%s) = %q, want %q", file, v.bucket, want)
+		}
+		if want != "" && !strings.HasPrefix(v.detail, "added in ") {
+			t.Errorf("check(%s) detail = %q", file, v.detail)
+		}
+	}
+}
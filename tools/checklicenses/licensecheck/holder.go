@@ -0,0 +1,47 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"regexp"
+	"strings"
+)
+
+// copyrightLine extracts the holder from a conventional copyright line like
+// "Copyright 2017-2018 the u-root Authors. All rights reserved".
+var copyrightLine = regexp.MustCompile(`(?m)Copyright (?:\([cC]\) |© )?[0-9][0-9, -]*\s+(.*?)\.?\s*(?:All rights reserved\.?)?\s*$`)
+
+// holder returns the copyright holder named in the license header of file,
+// and false if there is none. Files licensed only by an SPDX tag may have no
+// header at all.
+func (c *Config) holder(file string, contents []byte) (string, bool) {
+	texts := c.headers(file, contents)
+	if len(texts) == 0 {
+		return "", false
+	}
+	if re, text := firstMatch(c.licensesRegexps, texts); re != nil {
+		if i := re.SubexpIndex("holder"); i >= 0 {
+			if m := re.FindSubmatch(text); m != nil {
+				return strings.TrimSpace(string(m[i])), true
+			}
+		}
+	}
+	// Prefer the stripped header, which excludes the code below it.
+	text := texts[len(texts)-1]
+	if m := copyrightLine.FindSubmatch(text); m != nil {
+		return string(m[1]), true
+	}
+	return "", false
+}
+
+// wrongHolder reports whether the header of file names a copyright holder
+// other than the configured Owner, as folded by foldHolder.
+func (c *Config) wrongHolder(file string, contents []byte) bool {
+	if c.Owner == "" {
+		return false
+	}
+	h, ok := c.holder(file, contents)
+	return ok && c.foldHolder(h) != c.foldHolder(c.Owner)
+}
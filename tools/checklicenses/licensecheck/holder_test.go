@@ -0,0 +1,81 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"testing"
+)
+
+func TestHolder(t *testing.T) {
+	c := &Config{
+		Licenses: []License{
+			{Lines: []string{`^// Copyright \d+ (?P<holder>[^.]+)\. All rights reserved`, "// SPDX"}},
+			{Lines: []string{`^// Copyright`}},
+		},
+		Owner: "the u-root Authors",
+	}
+	if err := c.CompileRegexps(); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		in    string
+		want  string
+		wrong bool
+	}{
+		{in: "// Copyright 2020 the u-root Authors. All rights reserved\n// SPDX\n", want: "the u-root Authors"},
+		{in: "// Copyright 2020 Upstream Project. All rights reserved\n// SPDX\n", want: "Upstream Project", wrong: true},
+		{in: "// Copyright 2017-2018 the u-root Authors. All rights reserved\n// BSD\n", want: "the u-root Authors"},
+		{in: "// Copyright (c) 2019, 2020 Google LLC.\n", want: "Google LLC", wrong: true},
+	} {
+		got, ok := c.holder("a.go", []byte(tt.in))
+		if !ok || got != tt.want {
+			t.Errorf("holder(%q) = %q, %t, want %q, true", tt.in, got, ok, tt.want)
+		}
+		if got := c.wrongHolder("a.go", []byte(tt.in)); got != tt.wrong {
+			t.Errorf("wrongHolder(%q) = %t, want %t", tt.in, got, tt.wrong)
+		}
+	}
+}
+
+func TestHolderSPDXOnly(t *testing.T) {
+	for _, mode := range []string{spdxModeOnly, spdxModeEither} {
+		c := &Config{Licenses: []License{{Lines: []string{`^<!-- Copyright`}}}, SPDXMode: mode, Owner: "the u-root Authors"}
+		if err := c.CompileRegexps(); err != nil {
+			t.Fatal(err)
+		}
+		const in = "<p>hi</p>\n<!-- SPDX-License-Identifier: MIT -->\n"
+		if got := c.checkContents("a.html", []byte(in)); got != "" {
+			t.Errorf("SPDXMode %q: checkContents(a.html, %q) = %q, want no violation", mode, in, got)
+		}
+	}
+}
+
+func TestFoldHolder(t *testing.T) {
+	const in = "// Copyright 2026 JÜRGEN Müller\n"
+	for _, tt := range []struct {
+		foldCase, stripAccents bool
+		owner                  string
+		wrong                  bool
+	}{
+		{owner: "JÜRGEN Müller"},
+		{owner: "Jürgen Müller", wrong: true},
+		{owner: "Jürgen Müller", foldCase: true},
+		{owner: "JURGEN Muller", wrong: true},
+		{owner: "JURGEN Muller", stripAccents: true},
+		{owner: "Jurgen Muller", stripAccents: true, wrong: true},
+		{owner: "jurgen muller", foldCase: true, stripAccents: true},
+		// A decomposed ü folds like the precomposed one.
+		{owner: "J\u0075\u0308rgen M\u0075\u0308ller", foldCase: true},
+		{owner: "J\u0055\u0308RGEN M\u0075\u0308ller", wrong: true},
+	} {
+		c := &Config{Licenses: []License{{Lines: []string{`^// Copyright`}}}, Owner: tt.owner, FoldHolderCase: tt.foldCase, StripHolderAccents: tt.stripAccents}
+		if err := c.CompileRegexps(); err != nil {
+			t.Fatal(err)
+		}
+		if got := c.wrongHolder("a.go", []byte(in)); got != tt.wrong {
+			t.Errorf("Owner %q, FoldHolderCase %t, StripHolderAccents %t: wrongHolder(%q) = %t, want %t", tt.owner, tt.foldCase, tt.stripAccents, in, got, tt.wrong)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// runHook runs the -post-hook command, split into words at spaces, with the
+// exit code of the run as an extra argument and the summary of the run on
+// its standard input. Its output goes to stderr, so as not to mix with the
+// report. The hook cannot change the exit code.
+func runHook(ctx context.Context, command string, sum *summary, configHash string, now time.Time, code int) error {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return fmt.Errorf("empty command")
+	}
+	in, err := sum.json(configHash, now)
+	if err != nil {
+		return err
+	}
+	cmd := exec.CommandContext(ctx, args[0], append(args[1:], strconv.Itoa(code))...)
+	cmd.Stdin = bytes.NewReader(in)
+	cmd.Stdout, cmd.Stderr = os.Stderr, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s: %v", command, err)
+	}
+	return nil
+}
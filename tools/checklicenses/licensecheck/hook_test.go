@@ -0,0 +1,49 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRunHook(t *testing.T) {
+	dir := t.TempDir()
+	hook := filepath.Join(dir, "hook")
+	script := "#!/bin/sh\necho \"$@\" > " + dir + "/args\ncat > " + dir + "/stdin\n"
+	if err := os.WriteFile(hook, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	sum := &summary{checked: 3, passed: 2, violations: 1, buckets: map[bucket]int{bucketMissing: 1}}
+	if err := runHook(context.Background(), hook+" --badge", sum, "abc", time.Now(), exitViolations); err != nil {
+		t.Fatal(err)
+	}
+	args, err := os.ReadFile(filepath.Join(dir, "args"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(args), "--badge 1\n"; got != want {
+		t.Errorf("hook arguments = %q, want %q", got, want)
+	}
+	stdin, err := os.ReadFile(filepath.Join(dir, "stdin"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got jsonSummary
+	if err := json.Unmarshal(stdin, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Checked != 3 || got.Buckets[bucketMissing] != 1 || got.ConfigHash != "abc" {
+		t.Errorf("hook stdin = %+v, want the summary", got)
+	}
+
+	if err := runHook(context.Background(), filepath.Join(dir, "missing"), sum, "abc", time.Now(), exitOK); err == nil {
+		t.Error("runHook() with a missing command succeeded, want error")
+	}
+}
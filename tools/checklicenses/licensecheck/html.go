@@ -0,0 +1,194 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"time"
+)
+
+// htmlReport is what the -html page shows.
+type htmlReport struct {
+	Generated  string
+	Summary    []summaryRow
+	Buckets    []htmlBucket
+	Violations []htmlViolation
+	Licenses   []htmlLicense
+}
+
+// htmlBucket counts the violations in one bucket.
+type htmlBucket struct {
+	Bucket   bucket
+	Severity string
+	N        int
+}
+
+// htmlViolation is one row of the table of violations.
+type htmlViolation struct {
+	Bucket   bucket
+	Severity string
+	Dir      string
+	File     string
+	License  string
+	Detail   string
+}
+
+// htmlLicense is the number of checked files carrying one license and
+// version.
+type htmlLicense struct {
+	License string
+	Version string
+	Files   int
+	Share   string
+}
+
+// htmlTemplate renders an htmlReport as a page without external assets.
+// Clicking a column heading sorts the table by that column.
+var htmlTemplate = htmltemplate.Must(htmltemplate.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>License report</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 0.25em 0.5em; text-align: left; vertical-align: top; }
+th { background: #eee; }
+table.sortable th { cursor: pointer; }
+td.n { text-align: right; }
+.error { color: #b00; }
+.warning { color: #a60; }
+</style>
+</head>
+<body>
+<h1>License report</h1>
+<p>Generated {{.Generated}}.</p>
+<h2>Summary</h2>
+<table>
+{{- range .Summary}}
+<tr><th>{{.Name}}</th><td class="n">{{.N}}</td></tr>
+{{- end}}
+</table>
+{{- if .Buckets}}
+<h2>Violations by bucket</h2>
+<table>
+<tr><th>Bucket</th><th>Severity</th><th>Files</th></tr>
+{{- range .Buckets}}
+<tr><td>{{.Bucket}}</td><td class="{{.Severity}}">{{.Severity}}</td><td class="n">{{.N}}</td></tr>
+{{- end}}
+</table>
+<h2>Violations</h2>
+<table class="sortable">
+<thead><tr><th>Bucket</th><th>Severity</th><th>Directory</th><th>File</th><th>License</th><th>Detail</th></tr></thead>
+<tbody>
+{{- range .Violations}}
+<tr><td>{{.Bucket}}</td><td class="{{.Severity}}">{{.Severity}}</td><td>{{.Dir}}</td><td>{{.File}}</td><td>{{.License}}</td><td>{{.Detail}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+{{- else}}
+<p>No violations.</p>
+{{- end}}
+{{- if .Licenses}}
+<h2>License coverage</h2>
+<table class="sortable">
+<thead><tr><th>License</th><th>Version</th><th>Files</th><th>Share</th></tr></thead>
+<tbody>
+{{- range .Licenses}}
+<tr><td>{{.License}}</td><td>{{.Version}}</td><td class="n">{{.Files}}</td><td class="n">{{.Share}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+{{- end}}
+<script>
+document.querySelectorAll("table.sortable").forEach(function(table) {
+	table.querySelectorAll("th").forEach(function(th, col) {
+		th.addEventListener("click", function() {
+			var body = table.tBodies[0];
+			var asc = th.dataset.order !== "asc";
+			th.dataset.order = asc ? "asc" : "desc";
+			Array.from(body.rows).sort(function(a, b) {
+				var x = a.cells[col].textContent, y = b.cells[col].textContent;
+				return (asc ? 1 : -1) * x.localeCompare(y, undefined, {numeric: true});
+			}).forEach(function(row) { body.appendChild(row); });
+		});
+	});
+});
+</script>
+</body>
+</html>
+`))
+
+// newHTMLReport returns the page showing sum, the violations and the
+// licenses counted by a run at time now. The violations are grouped by
+// bucket, in bucket order, then by directory.
+func newHTMLReport(config *Config, sum *summary, violations []violation, licenses licenseCounts, now time.Time) htmlReport {
+	r := htmlReport{Generated: now.Format(time.RFC3339), Summary: sum.rows()}
+	order := map[bucket]int{}
+	for i, b := range buckets {
+		order[b] = i
+	}
+	counts := map[bucket]int{}
+	for _, v := range violations {
+		file := displayPath(config, v.file)
+		r.Violations = append(r.Violations, htmlViolation{
+			Bucket:   v.bucket,
+			Severity: config.reportSeverity(v, now),
+			Dir:      path.Dir(file),
+			File:     file,
+			License:  v.license,
+			Detail:   v.detail,
+		})
+		counts[v.bucket]++
+	}
+	sort.SliceStable(r.Violations, func(i, j int) bool {
+		a, b := r.Violations[i], r.Violations[j]
+		if a.Bucket != b.Bucket {
+			return order[a.Bucket] < order[b.Bucket]
+		}
+		if a.Dir != b.Dir {
+			return a.Dir < b.Dir
+		}
+		return a.File < b.File
+	})
+	for _, b := range buckets {
+		if n := counts[b]; n > 0 {
+			r.Buckets = append(r.Buckets, htmlBucket{Bucket: b, Severity: config.severity(b), N: n})
+		}
+	}
+	rows, total := licenses.sorted()
+	for _, lv := range rows {
+		l := htmlLicense{License: lv.license, Version: lv.version, Files: licenses[lv]}
+		if l.License == "" {
+			l.License = "(none)"
+		}
+		l.Share = fmt.Sprintf("%.1f%%", 100*float64(l.Files)/float64(total))
+		r.Licenses = append(r.Licenses, l)
+	}
+	return r
+}
+
+// write renders the page to w.
+func (r htmlReport) write(w io.Writer) error {
+	return htmlTemplate.Execute(w, r)
+}
+
+// writeHTML writes the -html page of a run to file.
+func writeHTML(file string, config *Config, sum *summary, violations []violation, licenses licenseCounts, now time.Time) error {
+	var b bytes.Buffer
+	if err := newHTMLReport(config, sum, violations, licenses, now).write(&b); err != nil {
+		return fmt.Errorf("-html: %v", err)
+	}
+	if err := os.WriteFile(file, b.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("-html: %v", err)
+	}
+	return nil
+}
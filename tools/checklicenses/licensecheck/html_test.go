@@ -0,0 +1,57 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHTMLReport(t *testing.T) {
+	c := &Config{}
+	if err := c.CompileRegexps(); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC)
+	sum := &summary{checked: 4, passed: 1, violations: 3}
+	violations := []violation{
+		{file: "b/z.go", bucket: bucketYearRange},
+		{file: "b/y.go", bucket: bucketMissing},
+		{file: "a/x.go", bucket: bucketMissing, detail: "<script>"},
+	}
+	licenses := licenseCounts{{license: "BSD"}: 3, {}: 1}
+	r := newHTMLReport(c, sum, violations, licenses, now)
+
+	var files []string
+	for _, v := range r.Violations {
+		files = append(files, v.File)
+	}
+	if want := []string{"a/x.go", "b/y.go", "b/z.go"}; !reflect.DeepEqual(files, want) {
+		t.Errorf("violations = %q, want %q", files, want)
+	}
+	if want := []htmlBucket{{bucketMissing, severityError, 2}, {bucketYearRange, severityError, 1}}; !reflect.DeepEqual(r.Buckets, want) {
+		t.Errorf("buckets = %+v, want %+v", r.Buckets, want)
+	}
+	if want := []htmlLicense{{"BSD", "", 3, "75.0%"}, {"(none)", "", 1, "25.0%"}}; !reflect.DeepEqual(r.Licenses, want) {
+		t.Errorf("licenses = %+v, want %+v", r.Licenses, want)
+	}
+
+	var b bytes.Buffer
+	if err := r.write(&b); err != nil {
+		t.Fatal(err)
+	}
+	page := b.String()
+	for _, want := range []string{"<td>&lt;script&gt;</td>", "<th>checked</th><td class=\"n\">4</td>", "<td>a/x.go</td>"} {
+		if !strings.Contains(page, want) {
+			t.Errorf("page does not contain %q", want)
+		}
+	}
+	if strings.Contains(page, "<link") || strings.Contains(page, "src=") {
+		t.Error("page refers to external assets")
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// ignoreRule is a compiled pattern in gitignore syntax.
+type ignoreRule struct {
+	*regexp.Regexp
+	// negate re-includes the paths matched by an earlier rule.
+	negate bool
+	// dirOnly rules, written with a trailing slash, only match
+	// directories.
+	dirOnly bool
+	// index is the position of the pattern in Ignore.
+	index int
+}
+
+// readIgnoreFile returns the lines of a file in gitignore syntax.
+func readIgnoreFile(file string) ([]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var lines []string
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		lines = append(lines, s.Text())
+	}
+	return lines, s.Err()
+}
+
+// compileIgnore compiles a gitignore pattern. It returns false for blank
+// lines and comments, which hold no pattern.
+func compileIgnore(pattern string) (ignoreRule, bool, error) {
+	var r ignoreRule
+	p := strings.TrimSuffix(pattern, "\r")
+	for strings.HasSuffix(p, " ") && !strings.HasSuffix(p, `\ `) {
+		p = p[:len(p)-1]
+	}
+	if p == "" || p[0] == '#' {
+		return r, false, nil
+	}
+	switch {
+	case p[0] == '!':
+		r.negate, p = true, p[1:]
+	case strings.HasPrefix(p, `\!`), strings.HasPrefix(p, `\#`):
+		p = p[1:]
+	}
+	if strings.HasSuffix(p, "/") {
+		r.dirOnly, p = true, strings.TrimSuffix(p, "/")
+	}
+	// A slash anywhere but at the end anchors the pattern to the top;
+	// otherwise it matches at any depth.
+	anchored := strings.Contains(p, "/")
+	p = strings.TrimPrefix(p, "/")
+
+	var b strings.Builder
+	b.WriteString("^")
+	if !anchored {
+		b.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(p); i++ {
+		atElem := i == 0 || p[i-1] == '/'
+		switch c := p[i]; {
+		case atElem && strings.HasPrefix(p[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += len("**/") - 1
+		case atElem && p[i:] == "**":
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '[' && strings.IndexByte(p[i+1:], ']') > 0:
+			j := i + 1 + strings.IndexByte(p[i+1:], ']')
+			class := p[i+1 : j]
+			if class[0] == '!' {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i = j
+		case c == '\\' && i+1 < len(p):
+			i++
+			b.WriteString(regexp.QuoteMeta(p[i : i+1]))
+		default:
+			b.WriteString(regexp.QuoteMeta(p[i : i+1]))
+		}
+	}
+	b.WriteString("$")
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return r, false, fmt.Errorf("invalid pattern %q: %v", pattern, err)
+	}
+	r.Regexp = re
+	return r, true, nil
+}
+
+// compileIgnores compiles the Ignore patterns.
+func (c *Config) compileIgnores() error {
+	c.ignore = nil
+	for i, p := range c.Ignore {
+		r, ok, err := compileIgnore(p)
+		if err != nil {
+			return fmt.Errorf("Ignore[%d]: %v", i, err)
+		}
+		if ok {
+			r.index = i
+			c.ignore = append(c.ignore, r)
+		}
+	}
+	return nil
+}
+
+// addIgnore adds gitignore patterns, like the lines of an -ignore-file, to
+// the Ignore patterns.
+func (c *Config) addIgnore(patterns []string) error {
+	c.Ignore = append(c.Ignore, patterns...)
+	return c.compileIgnores()
+}
+
+// ignored reports whether the Ignore patterns exclude file, and returns the
+// index in Ignore of the pattern deciding so. As in git, the last matching
+// pattern wins, and files cannot be re-included once a directory holding
+// them is excluded.
+func (c *Config) ignored(file string) (int, bool) {
+	if len(c.ignore) == 0 {
+		return -1, false
+	}
+	for i := 0; i < len(file); i++ {
+		if file[i] == '/' {
+			if j, ok := c.ignoreMatch(file[:i], true); ok {
+				return j, true
+			}
+		}
+	}
+	return c.ignoreMatch(file, false)
+}
+
+// ignoreMatch reports whether the last Ignore pattern matching p excludes
+// it, and returns the index in Ignore of that pattern, or -1 if none
+// matches. dir tells whether p is a directory.
+func (c *Config) ignoreMatch(p string, dir bool) (int, bool) {
+	last, ignored := -1, false
+	for _, r := range c.ignore {
+		if r.dirOnly && !dir {
+			continue
+		}
+		if r.MatchString(p) {
+			last, ignored = r.index, !r.negate
+		}
+	}
+	return last, ignored
+}
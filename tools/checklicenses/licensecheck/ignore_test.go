@@ -0,0 +1,78 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import "testing"
+
+func TestIgnored(t *testing.T) {
+	c := &Config{}
+	if err := c.addIgnore([]string{
+		"# generated assets",
+		"",
+		"*.pb.go",
+		"!keep.pb.go",
+		"/docs",
+		"build/",
+		"pkg/**/testdata/*.txt",
+		"**/fixtures",
+		"third_party/",
+		"!third_party/ours.go",
+		`\#hash.go`,
+		"tmp[0-9].go",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	for file, want := range map[string]bool{
+		"a.go":                         false,
+		"a.pb.go":                      true,
+		"pkg/x/a.pb.go":                true,
+		"pkg/x/keep.pb.go":             false,
+		"docs/index.go":                true,
+		"pkg/docs/index.go":            false,
+		"build/out.go":                 true,
+		"pkg/build/out.go":             true,
+		"build.go":                     false,
+		"pkg/a/b/testdata/x.txt":       true,
+		"pkg/testdata/x.txt":           true,
+		"cmds/testdata/x.txt":          false,
+		"a/b/fixtures/f.go":            true,
+		"third_party/ours.go":          true,
+		"#hash.go":                     true,
+		"tmp1.go":                      true,
+		"tmpa.go":                      false,
+		"pkg/a/b/testdata/sub/x.txt":   false,
+		"cmds/core/pkg/docs/readme.go": false,
+	} {
+		if _, got := c.ignored(file); got != want {
+			t.Errorf("ignored(%q) = %t, want %t", file, got, want)
+		}
+	}
+	// The deciding pattern is the last matching one, or the one excluding
+	// a directory, counting blank lines and comments.
+	for file, want := range map[string]int{
+		"a.pb.go":             2,
+		"pkg/x/keep.pb.go":    3,
+		"pkg/build/out.go":    5,
+		"third_party/ours.go": 8,
+		"a.go":                -1,
+	} {
+		if got, _ := c.ignored(file); got != want {
+			t.Errorf("ignored(%q) decided by Ignore[%d], want Ignore[%d]", file, got, want)
+		}
+	}
+}
+
+func TestIgnoreRejects(t *testing.T) {
+	c := &Config{Accept: []string{".*\\.go"}, Ignore: []string{"vendor/"}}
+	if err := c.CompileRegexps(); err != nil {
+		t.Fatal(err)
+	}
+	if c.included("vendor/a/a.go") {
+		t.Errorf("included(vendor/a/a.go) = true, want false")
+	}
+	if !c.included("cmds/a.go") {
+		t.Errorf("included(cmds/a.go) = false, want true")
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// cgroupRoot is where the cgroup file system is mounted.
+const cgroupRoot = "/sys/fs/cgroup"
+
+// defaultJobs returns the number of files checked concurrently without -j:
+// the value of the environment variable env if it is set, or else the CPU
+// quota of the cgroup under root, or else the number of CPUs. Containers
+// often see all the CPUs of the machine but may only use a few of them.
+func defaultJobs(env, root string) (int, error) {
+	if env != "" {
+		if v := os.Getenv(env); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("-jobs-from-env: %s=%q is not a positive number", env, v)
+			}
+			return n, nil
+		}
+	}
+	n := runtime.NumCPU()
+	if q := cgroupCPUs(root); q > 0 && q < n {
+		n = q
+	}
+	return n, nil
+}
+
+// cgroupCPUs returns the number of CPUs the CPU quota of the cgroup under
+// root allows, rounded up, or 0 if there is no quota. It reads cpu.max of
+// cgroup v2, or else cpu.cfs_quota_us and cpu.cfs_period_us of cgroup v1.
+func cgroupCPUs(root string) int {
+	if b, err := os.ReadFile(filepath.Join(root, "cpu.max")); err == nil {
+		// "max 100000", or the quota and the period, e.g. "200000 100000".
+		f := strings.Fields(string(b))
+		if len(f) != 2 || f[0] == "max" {
+			return 0
+		}
+		return quotaCPUs(f[0], f[1])
+	}
+	quota, err := os.ReadFile(filepath.Join(root, "cpu", "cpu.cfs_quota_us"))
+	if err != nil {
+		return 0
+	}
+	period, err := os.ReadFile(filepath.Join(root, "cpu", "cpu.cfs_period_us"))
+	if err != nil {
+		return 0
+	}
+	// A quota of -1 means none.
+	return quotaCPUs(strings.TrimSpace(string(quota)), strings.TrimSpace(string(period)))
+}
+
+// quotaCPUs returns the number of CPUs a quota of CPU time per period
+// allows, rounded up, or 0 if they are not positive numbers.
+func quotaCPUs(quota, period string) int {
+	q, err := strconv.ParseInt(quota, 10, 64)
+	if err != nil || q <= 0 {
+		return 0
+	}
+	p, err := strconv.ParseInt(period, 10, 64)
+	if err != nil || p <= 0 {
+		return 0
+	}
+	return int((q + p - 1) / p)
+}
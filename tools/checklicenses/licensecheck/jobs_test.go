@@ -0,0 +1,61 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestCgroupCPUs(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		files map[string]string
+		want  int
+	}{
+		{name: "no cgroup"},
+		{name: "v2 unlimited", files: map[string]string{"cpu.max": "max 100000\n"}},
+		{name: "v2 quota", files: map[string]string{"cpu.max": "200000 100000\n"}, want: 2},
+		{name: "v2 fraction", files: map[string]string{"cpu.max": "150000 100000\n"}, want: 2},
+		{name: "v2 garbage", files: map[string]string{"cpu.max": "lots\n"}},
+		{name: "v1 unlimited", files: map[string]string{"cpu/cpu.cfs_quota_us": "-1\n", "cpu/cpu.cfs_period_us": "100000\n"}},
+		{name: "v1 quota", files: map[string]string{"cpu/cpu.cfs_quota_us": "300000\n", "cpu/cpu.cfs_period_us": "100000\n"}, want: 3},
+		{name: "v1 no period", files: map[string]string{"cpu/cpu.cfs_quota_us": "300000\n"}},
+	} {
+		root := t.TempDir()
+		writeTree(t, root, tt.files)
+		if got := cgroupCPUs(root); got != tt.want {
+			t.Errorf("%s: cgroupCPUs() = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDefaultJobs(t *testing.T) {
+	limited := t.TempDir()
+	writeTree(t, limited, map[string]string{"cpu.max": "100000 100000\n"})
+	none := filepath.Join(t.TempDir(), "missing")
+
+	t.Setenv("TEST_NPROC", "")
+	for _, tt := range []struct {
+		name, env, value, root string
+		want                   int
+		err                    bool
+	}{
+		{name: "CPUs", env: "TEST_NPROC", root: none, want: runtime.NumCPU()},
+		{name: "quota", env: "TEST_NPROC", root: limited, want: 1},
+		{name: "environment", env: "TEST_NPROC", value: "7", root: limited, want: 7},
+		{name: "environment ignored", value: "7", root: none, want: runtime.NumCPU()},
+		{name: "invalid", env: "TEST_NPROC", value: "many", root: none, err: true},
+		{name: "zero", env: "TEST_NPROC", value: "0", root: none, err: true},
+	} {
+		os.Setenv("TEST_NPROC", tt.value)
+		got, err := defaultJobs(tt.env, tt.root)
+		if (err != nil) != tt.err || got != tt.want {
+			t.Errorf("%s: defaultJobs(%q) with %q = %d, %v, want %d, error %t", tt.name, tt.env, tt.value, got, err, tt.want, tt.err)
+		}
+	}
+}
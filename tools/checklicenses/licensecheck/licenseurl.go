@@ -0,0 +1,44 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"regexp"
+	"strings"
+)
+
+// urlPattern matches http and https URLs in text.
+var urlPattern = regexp.MustCompile(`https?://[^\s<>"'()]+`)
+
+// normalizeURL returns u without its scheme, a trailing slash or trailing
+// punctuation, and with its host lower-cased, so that equivalent references
+// compare equal.
+func normalizeURL(u string) string {
+	u = strings.TrimRight(u, ".,;:")
+	u = strings.TrimPrefix(strings.TrimPrefix(u, "http://"), "https://")
+	u = strings.TrimSuffix(u, "/")
+	if i := strings.IndexByte(u, '/'); i >= 0 {
+		return strings.ToLower(u[:i]) + u[i:]
+	}
+	return strings.ToLower(u)
+}
+
+// licenseURL reports whether the header region of file refers to one of the
+// LicenseURLs.
+func (c *Config) licenseURL(file string, contents []byte) bool {
+	if len(c.licenseURLs) == 0 {
+		return false
+	}
+	style, ok := c.styleFor(file, contents)
+	if !ok {
+		style = cStyle
+	}
+	for _, u := range urlPattern.FindAll(c.stripHeader(style, contents), -1) {
+		if c.licenseURLs[normalizeURL(string(u))] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,29 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import "testing"
+
+func TestLicenseURL(t *testing.T) {
+	c := &Config{LicenseURLs: []string{"https://example.com/LICENSE/"}}
+	if err := c.CompileRegexps(); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		in   string
+		want bucket
+	}{
+		{"// License: https://example.com/LICENSE\npackage a\n", ""},
+		{"// License: http://EXAMPLE.com/LICENSE/.\npackage a\n", ""},
+		{"/*\n * See <https://example.com/LICENSE>\n */\npackage a\n", ""},
+		{"// License: https://example.com/license\npackage a\n", bucketMissing},
+		{"// License: https://example.org/LICENSE\npackage a\n", bucketMissing},
+		{"package a\n\n// https://example.com/LICENSE\n", bucketMissing},
+	} {
+		if got := c.checkContents("a.go", []byte(tt.in)); got != tt.want {
+			t.Errorf("checkContents(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// compileLines compiles each line of the license regexp on its own, for
+// locating where a header departs from it. Licenses whose lines are not
+// regexps on their own, like those with a group spanning lines, cannot be
+// located and get none.
+func (l *License) compileLines(ignoreYears bool) {
+	l.lineRegexps = nil
+	res := make([]*regexp.Regexp, 0, len(l.Lines))
+	for i, line := range l.Lines {
+		if i == 0 {
+			line = strings.TrimPrefix(line, "^")
+		}
+		if ignoreYears {
+			line = literalYears.ReplaceAllLiteralString(line, anyYears)
+		}
+		re, err := regexp.Compile("^(?:" + line + ")$")
+		if err != nil {
+			return
+		}
+		res = append(res, re)
+	}
+	l.lineRegexps = res
+}
+
+// mismatch describes the first line where a header departs from a license.
+type mismatch struct {
+	license string
+	// line is the 1-based line number in the file.
+	line int
+	// got is the line of the file, and want the line of the license.
+	got, want string
+}
+
+func (m mismatch) String() string {
+	return fmt.Sprintf("line %d departs from %s: got %q, want %q", m.line, m.license, m.got, m.want)
+}
+
+// firstMismatch compares the header of file line by line with each of
+// Licenses, and returns where it departs from the one whose leading lines it
+// matches the most. Each line of a license may match either the line of the
+// file as written or its text without comment markers. It returns false if
+// no license can be compared line by line, or if the header matches one all
+// the way yet fails as a whole.
+func (c *Config) firstMismatch(file string, contents []byte) (mismatch, bool) {
+	style, ok := c.styleFor(file, contents)
+	if !ok {
+		style = cStyle
+	}
+	lines := strings.Split(string(contents), "\n")
+	first := 0
+	if bytes.HasPrefix(contents, []byte("#!")) {
+		first = 1
+	}
+	var best mismatch
+	bestMatched := -1
+	for i := range c.Licenses {
+		res := c.Licenses[i].lineRegexps
+		if len(res) == 0 {
+			continue
+		}
+		matched, inBlock := 0, false
+		m := mismatch{license: c.Licenses[i].id("Licenses", i)}
+		for n := first; ; n++ {
+			if n >= len(lines) {
+				m.line, m.want = n+1, c.Licenses[i].Lines[matched]
+				break
+			}
+			raw := strings.TrimRight(lines[n], "\r")
+			text, ok := style.stripLine([]byte(strings.TrimSpace(raw)), &inBlock)
+			if ok && text == nil {
+				// A line holding nothing but a comment delimiter.
+				continue
+			}
+			if !res[matched].MatchString(raw) && !res[matched].Match(text) {
+				m.line, m.got, m.want = n+1, raw, c.Licenses[i].Lines[matched]
+				break
+			}
+			if matched++; matched == len(res) {
+				// The header fails for another reason.
+				return mismatch{}, false
+			}
+		}
+		if matched > bestMatched {
+			best, bestMatched = m, matched
+		}
+	}
+	return best, bestMatched >= 0
+}
@@ -0,0 +1,91 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import "testing"
+
+func TestFirstMismatch(t *testing.T) {
+	c := &Config{Licenses: []License{
+		{Name: "u-root", Lines: []string{
+			`^// Copyright [\d\-, ]+ the u-root Authors\. All rights reserved`,
+			"// Use of this source code is governed by a BSD-style",
+			`// license that can be found in the LICENSE file\.`,
+		}},
+		{Name: "plain", Lines: []string{`^Copyright \d+ X`, "", "Apache"}},
+		{Name: "multiline", Lines: []string{"^(a", "b)"}},
+	}}
+	if err := c.CompileRegexps(); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		name string
+		file string
+		in   string
+		want mismatch
+		ok   bool
+	}{
+		{
+			name: "typo",
+			file: "a.go",
+			in:   "// Copyright 2026 the u-root Authors. All rights reserved\n// Use of this source code is governed by a BSD style\n// license that can be found in the LICENSE file.\n\npackage a\n",
+			want: mismatch{license: "u-root", line: 2, got: "// Use of this source code is governed by a BSD style", want: "// Use of this source code is governed by a BSD-style"},
+			ok:   true,
+		},
+		{
+			name: "truncated",
+			file: "a.go",
+			in:   "// Copyright 2026 the u-root Authors. All rights reserved\n\npackage a\n",
+			want: mismatch{license: "u-root", line: 2, got: "", want: "// Use of this source code is governed by a BSD-style"},
+			ok:   true,
+		},
+		{
+			name: "plain text in a block comment",
+			file: "a.sh",
+			in:   "#!/bin/sh\n# Copyright 2026 X\n#\n# MIT\n",
+			want: mismatch{license: "plain", line: 4, got: "# MIT", want: "Apache"},
+			ok:   true,
+		},
+		{
+			name: "end of file",
+			file: "a.go",
+			in:   "// Copyright 2026 the u-root Authors. All rights reserved\n// Use of this source code is governed by a BSD-style",
+			want: mismatch{license: "u-root", line: 3, want: `// license that can be found in the LICENSE file\.`},
+			ok:   true,
+		},
+		{
+			name: "matches every line",
+			file: "a.go",
+			in:   "// Copyright 2026 the u-root Authors. All rights reserved\n// Use of this source code is governed by a BSD-style\n// license that can be found in the LICENSE file.\n",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := c.firstMismatch(tt.file, []byte(tt.in))
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("firstMismatch(%q) = %+v, %t, want %+v, %t", tt.in, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+	if res := c.Licenses[2].lineRegexps; res != nil {
+		t.Errorf("multiline license has line regexps %v, want none", res)
+	}
+}
+
+func TestShowMismatchLine(t *testing.T) {
+	c := &Config{Licenses: []License{{Name: "x", Lines: []string{"^// Copyright 2026 X", "// BSD"}}}}
+	if err := c.CompileRegexps(); err != nil {
+		t.Fatal(err)
+	}
+	const in = "// Copyright 2026 X\n// MIT\n\npackage a\n"
+	v := candidate{file: "a.go", config: c, opts: Options{ShowMismatch: true}}.classify("a.go", []byte(in))
+	if v.bucket != bucketMissing || v.closest != "x" || v.line != 2 {
+		t.Errorf("classify() = %q, closest to %q at line %d, want %q, closest to %q at line 2", v.bucket, v.closest, v.line, bucketMissing, "x")
+	}
+
+	// The fingerprint of the violation does not depend on the flag.
+	plain := candidate{file: "a.go", config: c}.classify("a.go", []byte(in))
+	if got, want := c.fingerprint(v), c.fingerprint(plain); got != want {
+		t.Errorf("fingerprint() with -show-mismatch = %s, want %s as without", got, want)
+	}
+}
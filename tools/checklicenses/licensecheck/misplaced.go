@@ -0,0 +1,43 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"path"
+)
+
+// misplacedHeader reports whether the Go file carries one of Licenses in a
+// comment after its package clause, where an editor may have moved it, and
+// returns where, as a description and the line of the comment. Only comments
+// within region, the leading part of contents where headers may appear,
+// count. Files which do not parse have no misplaced header.
+func (c *Config) misplacedHeader(file string, contents []byte, region int) (string, int, bool) {
+	if path.Ext(file) != ".go" {
+		return "", 0, false
+	}
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, file, contents, parser.ParseComments)
+	if err != nil {
+		return "", 0, false
+	}
+	for _, cg := range f.Comments {
+		if cg.Pos() < f.Package {
+			continue
+		}
+		start, end := fset.Position(cg.Pos()).Offset, fset.Position(cg.End()).Offset
+		if end > region {
+			break
+		}
+		if c.matchLicense(c.headers(file, contents[start:end])) >= 0 {
+			line := fset.Position(cg.Pos()).Line
+			return fmt.Sprintf("license at line %d, after the package clause at line %d",
+				line, fset.Position(f.Package).Line), line, true
+		}
+	}
+	return "", 0, false
+}
@@ -0,0 +1,69 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import "testing"
+
+func TestMisplacedHeader(t *testing.T) {
+	c := &Config{Licenses: []License{{Lines: []string{"^// Copyright 2026 X", "// BSD"}}}}
+	if err := c.CompileRegexps(); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		name   string
+		file   string
+		in     string
+		want   bucket
+		detail string
+		line   int
+	}{
+		{
+			name: "before package",
+			file: "a.go",
+			in:   "// Copyright 2026 X\n// BSD\n\npackage a\n",
+		},
+		{
+			name:   "after package",
+			file:   "a.go",
+			in:     "package a\n\n// Copyright 2026 X\n// BSD\n\nimport \"os\"\n",
+			want:   bucketMisplaced,
+			detail: "license at line 3, after the package clause at line 1",
+			line:   3,
+		},
+		{
+			name:   "after imports",
+			file:   "a.go",
+			in:     "// Package a does things.\npackage a\n\nimport \"os\"\n\n// Copyright 2026 X\n// BSD\n\nvar _ = os.Args\n",
+			want:   bucketMisplaced,
+			detail: "license at line 6, after the package clause at line 2",
+			line:   6,
+		},
+		{
+			name: "no license",
+			file: "a.go",
+			in:   "package a\n\n// Something else.\nvar x int\n",
+			want: bucketMissing,
+		},
+		{
+			name: "not go",
+			file: "a.c",
+			in:   "int x;\n// Copyright 2026 X\n// BSD\n",
+			want: bucketMissing,
+		},
+		{
+			name: "does not parse",
+			file: "a.go",
+			in:   "package a\n\n// Copyright 2026 X\n// BSD\nfunc {\n",
+			want: bucketMissing,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			v := candidate{file: tt.file, config: c}.classify(tt.file, []byte(tt.in))
+			if v.bucket != tt.want || v.detail != tt.detail || v.line != tt.line {
+				t.Errorf("classify(%q) = %q, %q at line %d, want %q, %q at line %d", tt.in, v.bucket, v.detail, v.line, tt.want, tt.detail, tt.line)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// headerText returns the plain text of the license header of file, without
+// comment markers, or nil if it has none.
+func (c *Config) headerText(file string, contents []byte) []byte {
+	contents, _ = c.maskVolatile(c.headerRegion(contents))
+	if bannerExts[path.Ext(file)] {
+		if banner := leadingBanner(contents); banner != nil {
+			return cStyle.stripHeader(banner)
+		}
+	}
+	style, ok := c.styleFor(file, contents)
+	if !ok {
+		style = cStyle
+	}
+	if style == markupStyle {
+		return markupStyle.stripHeader(leadingMarkupComment(contents))
+	}
+	return c.stripHeader(style, contents)
+}
+
+// shortHeader describes how the header of file falls short of the MinLines
+// of Licenses[i], which it matched, or returns "" if it does not.
+func (c *Config) shortHeader(i int, file string, contents []byte) string {
+	want := c.Licenses[i].MinLines
+	if want == 0 {
+		return ""
+	}
+	n := 0
+	for _, line := range bytes.Split(c.headerText(file, contents), []byte("\n")) {
+		if len(bytes.TrimSpace(line)) > 0 {
+			n++
+		}
+	}
+	if n >= want {
+		return ""
+	}
+	return fmt.Sprintf("header has %d lines, %s requires at least %d", n, c.Licenses[i].id("Licenses", i), want)
+}
+
+// missingReference reports whether the header of file lacks the text which
+// Licenses[i], which it matched, requires. Whitespace is not significant, so
+// that the reference may wrap across comment lines.
+func (c *Config) missingReference(i int, file string, contents []byte) bool {
+	want := strings.Fields(c.Licenses[i].Require)
+	if len(want) == 0 {
+		return false
+	}
+	header := strings.Fields(string(c.headerText(file, contents)))
+	return !strings.Contains(strings.Join(header, " "), strings.Join(want, " "))
+}
@@ -0,0 +1,66 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import "testing"
+
+func TestMissingReference(t *testing.T) {
+	c := &Config{Licenses: []License{
+		{Lines: []string{"Licensed under the Apache License"}, Require: "see the NOTICE file"},
+		{Lines: []string{"BSD-style"}},
+	}}
+	if err := c.CompileRegexps(); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		file string
+		in   string
+		want bucket
+	}{
+		{"a.go", "// Licensed under the Apache License; see the NOTICE file.\npackage a\n", ""},
+		{"a.go", "// Licensed under the Apache License; see\n// the NOTICE file.\npackage a\n", ""},
+		{"a.go", "// Licensed under the Apache License.\npackage a\n", bucketMissingReference},
+		{"a.go", "// Licensed under the Apache License.\npackage a\n\n// see the NOTICE file\n", bucketMissingReference},
+		{"a.sh", "#!/bin/sh\n# Licensed under the Apache License,\n# see the NOTICE file\n", ""},
+		{"a.html", "<!-- Licensed under the Apache License, see the NOTICE file -->\n<p>\n", ""},
+		{"a.go", "// BSD-style\npackage a\n", ""},
+	} {
+		if got := c.checkContents(tt.file, []byte(tt.in)); got != tt.want {
+			t.Errorf("checkContents(%s, %q) = %q, want %q", tt.file, tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestShortHeader(t *testing.T) {
+	c := &Config{Licenses: []License{
+		{Name: "BSD", Lines: []string{"^// Copyright"}, MinLines: 3},
+		{Lines: []string{"^# Copyright"}},
+	}}
+	if err := c.CompileRegexps(); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		file string
+		in   string
+		want bucket
+	}{
+		{"a.go", "// Copyright 2026 X\n// Use of this source code is governed by a BSD-style\n// license that can be found in the LICENSE file.\npackage a\n", ""},
+		{"a.go", "// Copyright 2026 X\n//\n// BSD-style\n// LICENSE\npackage a\n", ""},
+		{"a.go", "// Copyright 2026 X\npackage a\n", bucketShortHeader},
+		{"a.go", "// Copyright 2026 X\n//\n//\n\npackage a\n", bucketShortHeader},
+		{"a.sh", "# Copyright 2026 X\necho\n", ""},
+	} {
+		if got := c.checkContents(tt.file, []byte(tt.in)); got != tt.want {
+			t.Errorf("checkContents(%s, %q) = %q, want %q", tt.file, tt.in, got, tt.want)
+		}
+	}
+	if got, want := c.shortHeader(0, "a.go", []byte("// Copyright 2026 X\npackage a\n")), "header has 1 lines, BSD requires at least 3"; got != want {
+		t.Errorf("shortHeader() = %q, want %q", got, want)
+	}
+	bad := &Config{Licenses: []License{{Lines: []string{"x"}, MinLines: -1}}}
+	if err := bad.CompileRegexps(); err == nil {
+		t.Error("CompileRegexps() with negative MinLines = nil, want error")
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"sync/atomic"
+	"text/tabwriter"
+	"time"
+)
+
+// licenseProfile accumulates how often a license was the winning match and
+// how long matching it took. It is safe for concurrent use.
+type licenseProfile struct {
+	tries int64
+	hits  int64
+	nanos int64
+}
+
+func (p *licenseProfile) add(d time.Duration, hit bool) {
+	atomic.AddInt64(&p.tries, 1)
+	atomic.AddInt64(&p.nanos, int64(d))
+	if hit {
+		atomic.AddInt64(&p.hits, 1)
+	}
+}
+
+// enableProfile starts collecting match statistics for the licenses of c and
+// of its AssetDirs.
+func (c *Config) enableProfile() {
+	c.profile = make([]licenseProfile, len(c.licensesRegexps))
+	for _, a := range c.AssetDirs {
+		a.config.enableProfile()
+	}
+}
+
+// printProfile writes the statistics collected since enableProfile for the
+// -c configuration called top, and for the per-directory configurations
+// loaded since, by the file they were loaded from. Since the first matching
+// license wins, licenses with many hits should come first to keep the total
+// time down.
+func (cc *configCache) printProfile(w io.Writer, top string) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	var dirs []*scopedConfig
+	seen := map[*scopedConfig]bool{cc.top: true}
+	for _, c := range cc.dirs {
+		if !seen[c] {
+			seen[c] = true
+			dirs = append(dirs, c)
+		}
+	}
+	sort.Slice(dirs, func(i, j int) bool { return dirs[i].dir < dirs[j].dir })
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "CONFIG\tLICENSE\tHITS\tTRIES\tTIME\tTIME/TRY")
+	cc.top.writeProfile(tw, top)
+	for _, c := range dirs {
+		c.writeProfile(tw, path.Join(c.dir, dirConfigName))
+	}
+	tw.Flush()
+}
+
+// writeProfile writes a line for each license of c and of its AssetDirs, as
+// the configuration called name.
+func (c *Config) writeProfile(w io.Writer, name string) {
+	for i, p := range c.profile {
+		license := fmt.Sprintf("Licenses[%d]", i)
+		if n := c.Licenses[i].Name; n != "" {
+			license += " (" + n + ")"
+		}
+		avg := time.Duration(0)
+		if p.tries > 0 {
+			avg = time.Duration(p.nanos / p.tries)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%v\t%v\n", name, license, p.hits, p.tries, time.Duration(p.nanos), avg)
+	}
+	for i, a := range c.AssetDirs {
+		a.config.writeProfile(w, fmt.Sprintf("%s AssetDirs[%d]", name, i))
+	}
+}
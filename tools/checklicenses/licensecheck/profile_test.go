@@ -0,0 +1,63 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"bytes"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestProfile(t *testing.T) {
+	c := &Config{Licenses: []License{
+		{Name: "bsd", Lines: []string{"^// BSD"}},
+		{Name: "apache", Lines: []string{"^// Apache"}},
+	}}
+	if err := c.CompileRegexps(); err != nil {
+		t.Fatal(err)
+	}
+	c.enableProfile()
+	for _, s := range []string{"// Apache\n", "// Apache\n", "// BSD\n", "none\n"} {
+		c.match("a.go", []byte(s))
+	}
+	for i, want := range []struct{ hits, tries int64 }{{1, 4}, {2, 3}} {
+		if p := c.profile[i]; p.hits != want.hits || p.tries != want.tries {
+			t.Errorf("profile[%d] = %d hits in %d tries, want %d in %d", i, p.hits, p.tries, want.hits, want.tries)
+		}
+	}
+	var b bytes.Buffer
+	newConfigCache(c, Options{}).printProfile(&b, "config.json")
+	if !strings.Contains(b.String(), "config.json  Licenses[1] (apache)  2") {
+		t.Errorf("printProfile() = %q, want a line for apache with 2 hits", b.String())
+	}
+}
+
+func TestProfileScopedConfigs(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"config.json":                `{"licenses": [["^// Copyright \\d+ X"]], "accept": [".*\\.go"]}`,
+		"tree/a.go":                  "// Copyright 2026 X\npackage a\n",
+		"tree/team/" + dirConfigName: `{"licenses": [["^// Team"]], "accept": [".*\\.go"], "assetdirs": [{"dirglob": "web", "licenses": [["^// Web"]]}]}`,
+		"tree/team/b.go":             "// Team\npackage b\n",
+		"tree/team/web/c.go":         "// Web\npackage c\n",
+		"tree/team/web/d.go":         "// Web\npackage d\n",
+	})
+	config := filepath.Join(dir, "config.json")
+	code, _, stderr := runFlags("-c", config, "-walk", filepath.Join(dir, "tree"), "-profile-config")
+	if code != exitOK {
+		t.Fatalf("run = %d, want %d; stderr:\n%s", code, exitOK, stderr)
+	}
+	for _, want := range []string{
+		regexp.QuoteMeta(config) + `\s+Licenses\[0\]\s+1\s+1\s`,
+		`team/\.licenserc\.json\s+Licenses\[0\]\s+1\s+1\s`,
+		`team/\.licenserc\.json AssetDirs\[0\]\s+Licenses\[0\]\s+2\s+2\s`,
+	} {
+		if !regexp.MustCompile(want).MatchString(stderr) {
+			t.Errorf("-profile-config printed\n%s\nwant a line matching %s", stderr, want)
+		}
+	}
+}
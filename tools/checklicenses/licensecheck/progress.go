@@ -0,0 +1,94 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// progressInterval is how often the progress line is refreshed.
+const progressInterval = time.Second
+
+// progress periodically prints how many files have been scanned to a
+// terminal.
+//
+// Done may be called from any goroutine. Stop must be called before the
+// final report is printed so that the two never interleave.
+type progress struct {
+	w     io.Writer
+	total int64
+	done  int64
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newProgress starts reporting progress toward total files to w, every
+// progressInterval. It returns nil when enabled is false or w is not a
+// terminal; a nil *progress is valid and does nothing.
+func newProgress(enabled bool, w io.Writer, total int) *progress {
+	f, ok := w.(*os.File)
+	if !enabled || !ok || !term.IsTerminal(int(f.Fd())) {
+		return nil
+	}
+	t := time.NewTicker(progressInterval)
+	p := startProgress(w, total, t.C)
+	go func() {
+		<-p.stop
+		t.Stop()
+	}()
+	return p
+}
+
+// startProgress starts reporting progress toward total files to w on each
+// tick.
+func startProgress(w io.Writer, total int, tick <-chan time.Time) *progress {
+	p := &progress{
+		w:     w,
+		total: int64(total),
+		stop:  make(chan struct{}),
+	}
+	p.wg.Add(1)
+	go p.loop(tick)
+	return p
+}
+
+func (p *progress) loop(tick <-chan time.Time) {
+	defer p.wg.Done()
+	for {
+		select {
+		case <-tick:
+			fmt.Fprintf(p.w, "\rscanned %d/%d", atomic.LoadInt64(&p.done), p.total)
+		case <-p.stop:
+			// Erase the progress line.
+			fmt.Fprint(p.w, "\r\x1b[K")
+			return
+		}
+	}
+}
+
+// Done records that one more file has been scanned.
+func (p *progress) Done() {
+	if p == nil {
+		return
+	}
+	atomic.AddInt64(&p.done, 1)
+}
+
+// Stop stops reporting and clears the progress line.
+func (p *progress) Stop() {
+	if p == nil {
+		return
+	}
+	close(p.stop)
+	p.wg.Wait()
+}
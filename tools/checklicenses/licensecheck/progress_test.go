@@ -0,0 +1,43 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestProgress(t *testing.T) {
+	var b bytes.Buffer
+	tick := make(chan time.Time)
+	p := startProgress(&b, 3, tick)
+	p.Done()
+	// Each tick is received once the line of the previous one is printed.
+	tick <- time.Now()
+	p.Done()
+	p.Done()
+	tick <- time.Now()
+	p.Stop()
+	if got, want := b.String(), "\rscanned 1/3\rscanned 3/3\r\x1b[K"; got != want {
+		t.Errorf("progress printed %q, want %q", got, want)
+	}
+}
+
+func TestProgressDisabled(t *testing.T) {
+	var b bytes.Buffer
+	for _, enabled := range []bool{false, true} {
+		// Only terminals show progress.
+		p := newProgress(enabled, &b, 3)
+		if p != nil {
+			t.Errorf("newProgress(%t, buffer) = %v, want nil", enabled, p)
+		}
+		p.Done()
+		p.Stop()
+	}
+	if b.Len() != 0 {
+		t.Errorf("disabled progress printed %q", b.String())
+	}
+}
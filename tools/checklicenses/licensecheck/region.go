@@ -0,0 +1,43 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// checkHeaderRegion returns an error if HeaderLines or HeaderBytes is
+// negative.
+func (c *Config) checkHeaderRegion() error {
+	if c.HeaderLines < 0 {
+		return fmt.Errorf("HeaderLines %d is negative", c.HeaderLines)
+	}
+	if c.HeaderBytes < 0 {
+		return fmt.Errorf("HeaderBytes %d is negative", c.HeaderBytes)
+	}
+	return nil
+}
+
+// headerRegion returns the leading part of contents which may hold the
+// license header: at most HeaderLines lines and HeaderBytes bytes, where
+// set.
+func (c *Config) headerRegion(contents []byte) []byte {
+	if c.HeaderBytes > 0 && len(contents) > c.HeaderBytes {
+		contents = contents[:c.HeaderBytes]
+	}
+	if c.HeaderLines > 0 {
+		end := 0
+		for n := 0; n < c.HeaderLines; n++ {
+			i := bytes.IndexByte(contents[end:], '\n')
+			if i < 0 {
+				return contents
+			}
+			end += i + 1
+		}
+		contents = contents[:end]
+	}
+	return contents
+}
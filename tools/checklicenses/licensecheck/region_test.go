@@ -0,0 +1,39 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import "testing"
+
+func TestHeaderRegion(t *testing.T) {
+	const late = "package a\n\n// Copyright 2026 X\n// BSD\n"
+	for _, tt := range []struct {
+		name            string
+		lines, bytes    int
+		in              string
+		want            bucket
+		wantCompileFail bool
+	}{
+		{name: "whole file", in: late},
+		{name: "within lines", lines: 4, in: late},
+		{name: "beyond lines", lines: 3, in: late, want: bucketMissing},
+		{name: "within bytes", bytes: len(late), in: late},
+		{name: "beyond bytes", bytes: len(late) - 2, in: late, want: bucketMissing},
+		{name: "short file", lines: 100, bytes: 1000, in: "// Copyright 2026 X\n// BSD"},
+		{name: "negative", lines: -1, wantCompileFail: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Config{Licenses: []License{{Lines: []string{"// Copyright 2026 X", "// BSD"}}}, HeaderLines: tt.lines, HeaderBytes: tt.bytes}
+			if err := c.CompileRegexps(); (err != nil) != tt.wantCompileFail {
+				t.Fatalf("CompileRegexps() = %v, want error %t", err, tt.wantCompileFail)
+			}
+			if tt.wantCompileFail {
+				return
+			}
+			if got := c.checkContents("a.go", []byte(tt.in)); got != tt.want {
+				t.Errorf("checkContents(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// contentHeadSize bounds how far into a file RejectContent looks.
+const contentHeadSize = 8 << 10
+
+// compileRejectContent compiles the RejectContent regexps.
+func (c *Config) compileRejectContent() error {
+	c.rejectContent = make([]*regexp.Regexp, 0, len(c.RejectContent))
+	for i, s := range c.RejectContent {
+		re, err := regexp.Compile(s)
+		if err != nil {
+			return fmt.Errorf("RejectContent[%d]: invalid regexp %q: %v", i, s, err)
+		}
+		c.rejectContent = append(c.rejectContent, re)
+	}
+	return nil
+}
+
+// contentRejected returns the RejectContent regexp matching the head of
+// contents, or "" if there is none.
+func (c *Config) contentRejected(contents []byte) string {
+	if len(contents) > contentHeadSize {
+		contents = contents[:contentHeadSize]
+	}
+	for i, re := range c.rejectContent {
+		if re.Match(contents) {
+			return fmt.Sprintf("RejectContent[%d] %q", i, c.RejectContent[i])
+		}
+	}
+	return ""
+}
@@ -0,0 +1,42 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRejectContent(t *testing.T) {
+	c := &Config{
+		Licenses:      []License{{Lines: []string{"^// Copyright"}}},
+		RejectContent: []string{`(?m)^// Generated by protoc-gen-\w+`},
+	}
+	if err := c.CompileRegexps(); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		in      string
+		skipped bool
+	}{
+		{"// Generated by protoc-gen-go\npackage a\n", true},
+		{"package a\n\n// Generated by protoc-gen-go\n", true},
+		{"package a\n" + strings.Repeat("\n", contentHeadSize) + "// Generated by protoc-gen-go\n", false},
+		{"package a\n", false},
+	} {
+		v := (candidate{file: "a.go", config: c}).classify("a.go", []byte(tt.in))
+		if (v.skipped != "") != tt.skipped {
+			t.Errorf("classify(%.40q) skipped = %q, want skipped %t", tt.in, v.skipped, tt.skipped)
+		}
+		if v.skipped != "" && v.bucket != "" {
+			t.Errorf("classify(%.40q) = %+v, skipped with a bucket", tt.in, v)
+		}
+	}
+
+	c = &Config{RejectContent: []string{"("}}
+	if err := c.CompileRegexps(); err == nil {
+		t.Error("CompileRegexps() with an invalid RejectContent succeeded, want error")
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// formats are the report formats accepted by -format.
+var formats = []string{"text", "json", "sarif"}
+
+// checkFormat returns an error if f is not one of formats.
+func checkFormat(f string) error {
+	for _, k := range formats {
+		if f == k {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown -format %q, want one of %v", f, formats)
+}
+
+// fingerprint returns a stable identifier of v, which downstream tools use to
+// recognize the same violation across runs. It depends only on the path with
+// GoPkg trimmed, the bucket and the license concerned, never on the order of
+// the report or on how paths are printed.
+func (c *Config) fingerprint(v violation) string {
+	sum := sha256.Sum256([]byte(c.trimPath(v.file) + "\x00" + string(v.bucket) + "\x00" + v.license))
+	return hex.EncodeToString(sum[:])
+}
+
+// jsonViolation is a violation in the JSON report.
+type jsonViolation struct {
+	File        string `json:"file"`
+	Bucket      bucket `json:"bucket"`
+	License     string `json:"license,omitempty"`
+	Detail      string `json:"detail,omitempty"`
+	Line        int    `json:"line,omitempty"`
+	Severity    string `json:"severity"`
+	Fingerprint string `json:"fingerprint"`
+}
+
+// writeReport writes the violations in format to w, as of time now.
+func writeReport(w io.Writer, format string, config *Config, violations []violation, now time.Time) error {
+	switch format {
+	case "sarif":
+		return writeSARIF(w, config, violations, now)
+	case "json":
+		report := struct {
+			Violations []jsonViolation `json:"violations"`
+		}{Violations: []jsonViolation{}}
+		for _, v := range violations {
+			report.Violations = append(report.Violations, jsonViolation{
+				File:        displayPath(config, v.file),
+				Bucket:      v.bucket,
+				License:     v.license,
+				Detail:      v.detail,
+				Line:        v.line,
+				Severity:    config.reportSeverity(v, now),
+				Fingerprint: config.fingerprint(v),
+			})
+		}
+		b, err := json.MarshalIndent(report, "", "\t")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", b)
+		return err
+	default:
+		for _, v := range violations {
+			if _, err := fmt.Fprintln(w, textLine(config, v)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// streamPrinter prints violations as they are found, one line each, from any
+// goroutine. Violations outside the -only buckets or in the baseline are
+// skipped. A nil streamPrinter prints nothing.
+type streamPrinter struct {
+	w        io.Writer
+	config   *Config
+	only     map[bucket]bool
+	baseline map[string]bool
+
+	mu sync.Mutex
+}
+
+func (p *streamPrinter) print(v violation) {
+	if p == nil || (p.only != nil && !p.only[v.bucket]) || p.baseline[p.config.trimPath(v.file)] {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fmt.Fprintln(p.w, textLine(p.config, v))
+}
+
+// textLine is the line of text reports for v: its path, followed by its
+// detail if it has one.
+func textLine(config *Config, v violation) string {
+	if v.detail == "" {
+		return displayPath(config, v.file)
+	}
+	return displayPath(config, v.file) + ": " + v.detail
+}
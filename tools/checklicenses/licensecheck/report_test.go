@@ -0,0 +1,69 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestFingerprint(t *testing.T) {
+	c := &Config{GoPkg: stringList{"github.com/u-root/u-root/"}}
+	if err := c.CompileRegexps(); err != nil {
+		t.Fatal(err)
+	}
+	v := violation{file: "github.com/u-root/u-root/a.go", bucket: bucketForbidden, license: "gpl"}
+	f := c.fingerprint(v)
+	if got := c.fingerprint(violation{file: "a.go", bucket: bucketForbidden, license: "gpl"}); got != f {
+		t.Errorf("fingerprint() depends on the GoPkg prefix")
+	}
+	for _, other := range []violation{
+		{file: "b.go", bucket: bucketForbidden, license: "gpl"},
+		{file: "a.go", bucket: bucketMissing, license: "gpl"},
+		{file: "a.go", bucket: bucketForbidden, license: "agpl"},
+	} {
+		if c.fingerprint(other) == f {
+			t.Errorf("fingerprint(%+v) = fingerprint(%+v)", other, v)
+		}
+	}
+}
+
+func TestWriteJSONReport(t *testing.T) {
+	c := &Config{}
+	var b bytes.Buffer
+	if err := writeReport(&b, "json", c, []violation{{file: "a.go", bucket: bucketMissing, line: 3}}, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		Violations []jsonViolation
+	}
+	if err := json.Unmarshal(b.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	want := jsonViolation{File: "a.go", Bucket: bucketMissing, Line: 3, Severity: severityError, Fingerprint: c.fingerprint(violation{file: "a.go", bucket: bucketMissing})}
+	if len(got.Violations) != 1 || got.Violations[0] != want {
+		t.Errorf("writeReport(json) = %+v, want [%+v]", got.Violations, want)
+	}
+}
+
+func TestStreamPrinter(t *testing.T) {
+	var b bytes.Buffer
+	p := &streamPrinter{
+		w:        &b,
+		config:   &Config{},
+		only:     map[bucket]bool{bucketMissing: true},
+		baseline: map[string]bool{"old.go": true},
+	}
+	p.print(violation{file: "a.go", bucket: bucketMissing})
+	p.print(violation{file: "b.go", bucket: bucketForbidden})
+	p.print(violation{file: "old.go", bucket: bucketMissing})
+	var nilPrinter *streamPrinter
+	nilPrinter.print(violation{file: "c.go", bucket: bucketMissing})
+	if got, want := b.String(), "a.go\n"; got != want {
+		t.Errorf("streamed %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// compileMustNotContain compiles the MustNotContain regexps of the license.
+// what and i locate it in the configuration for error messages.
+func (l *License) compileMustNotContain(what string, i int) error {
+	l.mustNotContain = make([]*regexp.Regexp, 0, len(l.MustNotContain))
+	for j, s := range l.MustNotContain {
+		re, err := regexp.Compile(s)
+		if err != nil {
+			return fmt.Errorf("%s[%d]: MustNotContain[%d]: invalid regexp %q: %v", what, i, j, s, err)
+		}
+		l.mustNotContain = append(l.mustNotContain, re)
+	}
+	return nil
+}
+
+// restriction describes the phrase in the header of file which one of the
+// MustNotContain regexps of Licenses[i], which it matched, finds, or returns
+// "" if there is none.
+func (c *Config) restriction(i int, file string, contents []byte) string {
+	res := c.Licenses[i].mustNotContain
+	if len(res) == 0 {
+		return ""
+	}
+	header := c.headerText(file, contents)
+	for _, re := range res {
+		if m := re.Find(header); m != nil {
+			return fmt.Sprintf("header contains %q", m)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,44 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import "testing"
+
+func TestMustNotContain(t *testing.T) {
+	c := &Config{Licenses: []License{{
+		Name:           "MIT",
+		Lines:          []string{"Permission is hereby granted"},
+		MustNotContain: []string{`(?i)for\s+non-commercial\s+use`, `(?i)not for resale`},
+	}}}
+	if err := c.CompileRegexps(); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		in   string
+		want violation
+	}{
+		{
+			in:   "// Permission is hereby granted.\npackage a\n",
+			want: violation{file: "a.go"},
+		},
+		{
+			in:   "// Permission is hereby granted for\n// non-commercial use.\npackage a\n",
+			want: violation{file: "a.go", bucket: bucketForbidden, license: "MIT", detail: `header contains "for\nnon-commercial use"`},
+		},
+		{
+			in:   "// Permission is hereby granted.\npackage a\n\n// Not for resale.\n",
+			want: violation{file: "a.go"},
+		},
+	} {
+		if got := (candidate{file: "a.go", config: c}).classify("a.go", []byte(tt.in)); got != tt.want {
+			t.Errorf("classify(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+
+	c = &Config{Licenses: []License{{Lines: []string{"x"}, MustNotContain: []string{"("}}}}
+	if err := c.CompileRegexps(); err == nil {
+		t.Error("CompileRegexps() with an invalid MustNotContain succeeded, want error")
+	}
+}
@@ -0,0 +1,200 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"time"
+)
+
+// cacheSchema is the version of the -cache file.
+const cacheSchema = 1
+
+// resultCache is what -cache records of a run: the result of checking each
+// file, so that the next run need not check the files which did not change
+// again, and the violations the run reported, for -quiet-unless-changed.
+type resultCache struct {
+	Schema int
+	// ConfigHash is the -config-hash of the run. Nothing recorded under
+	// another configuration is reused.
+	ConfigHash string
+	// Files holds the result of each checked file, by path as listed.
+	Files map[string]cachedResult
+	// Violations are the violations reported, as by violationSet.
+	Violations []string
+}
+
+// cachedResult is the result of checking one file.
+type cachedResult struct {
+	// Key hashes the contents of the file and everything else the result
+	// depends on, as returned by resultKey.
+	Key          string
+	Bucket       bucket     `json:",omitempty"`
+	License      string     `json:",omitempty"`
+	Closest      string     `json:",omitempty"`
+	Detail       string     `json:",omitempty"`
+	Line         int        `json:",omitempty"`
+	Carries      string     `json:",omitempty"`
+	Version      string     `json:",omitempty"`
+	Skipped      string     `json:",omitempty"`
+	EnforceAfter *time.Time `json:",omitempty"`
+}
+
+// newCachedResult returns the result recorded of v.
+func newCachedResult(v violation) cachedResult {
+	r := cachedResult{
+		Key:     v.resultKey,
+		Bucket:  v.bucket,
+		License: v.license,
+		Closest: v.closest,
+		Detail:  v.detail,
+		Line:    v.line,
+		Carries: v.carries,
+		Version: v.version,
+		Skipped: v.skipped,
+	}
+	if !v.enforceAfter.IsZero() {
+		t := v.enforceAfter
+		r.EnforceAfter = &t
+	}
+	return r
+}
+
+// violation returns the violation in file which r records.
+func (r cachedResult) violation(file string) violation {
+	v := violation{
+		file:      file,
+		bucket:    r.Bucket,
+		license:   r.License,
+		closest:   r.Closest,
+		detail:    r.Detail,
+		line:      r.Line,
+		carries:   r.Carries,
+		version:   r.Version,
+		skipped:   r.Skipped,
+		resultKey: r.Key,
+	}
+	if r.EnforceAfter != nil {
+		v.enforceAfter = *r.EnforceAfter
+	}
+	return v
+}
+
+// readCache returns the cache recorded in file. A missing file reads as an
+// empty cache.
+func readCache(file string) (*resultCache, error) {
+	r := &resultCache{}
+	b, err := os.ReadFile(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, r); err != nil {
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+	return r, nil
+}
+
+// writeCache records r in file.
+func writeCache(file string, r *resultCache) error {
+	b, err := json.MarshalIndent(r, "", "\t")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, append(b, '\n'), 0o644)
+}
+
+// valid reports whether r was recorded under the configuration hashed
+// as configHash, so that its results apply.
+func (r *resultCache) valid(configHash string) bool {
+	return r.Schema == cacheSchema && r.ConfigHash == configHash
+}
+
+// sameViolations reports whether r and prev record the same violations under
+// the same configuration.
+func (r *resultCache) sameViolations(prev *resultCache) bool {
+	if !prev.valid(r.ConfigHash) || len(r.Violations) != len(prev.Violations) {
+		return false
+	}
+	for i := range r.Violations {
+		if r.Violations[i] != prev.Violations[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// cacheCandidates readies candidates for reusing their results recorded in
+// prev, which only apply if they were recorded under configHash. The result
+// of a candidate also depends on the configuration governing it, which may
+// be that of its directory, and on the flags changing what classify reports.
+// Candidates checked as first committed are not cached, as their results
+// depend on the git history too.
+func cacheCandidates(candidates []candidate, prev *resultCache, configHash string) error {
+	hashes := map[*Config]string{}
+	for i := range candidates {
+		cd := &candidates[i]
+		if cd.addedIn != "" {
+			continue
+		}
+		h, ok := hashes[cd.config]
+		if !ok {
+			var err error
+			if h, err = cd.config.hash(currentPolicyOptions()); err != nil {
+				return err
+			}
+			hashes[cd.config] = h
+		}
+		b, err := json.Marshal(struct {
+			Config                     string
+			Vendored, FrontMatter      bool
+			FirstYear, LastYear        int
+			ShowMismatch, StrictSingle bool
+			CountLicenses              bool
+		}{h, cd.vendored, cd.frontMatter, cd.firstYear, cd.lastYear, cd.opts.ShowMismatch, cd.opts.StrictSingleLicense, cd.opts.countLicenses})
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(b)
+		cd.cacheKey = hex.EncodeToString(sum[:])
+		if r, ok := prev.Files[cd.file]; ok && prev.valid(configHash) {
+			cd.cached = &r
+		}
+	}
+	return nil
+}
+
+// resultKey returns the key of the result of checking contents as the file
+// name, for a candidate whose other inputs hash to cacheKey.
+func resultKey(cacheKey, name string, contents []byte) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00", cacheKey, name)
+	h.Write(contents)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// violationSet returns the violations as sorted "path: bucket" lines, with
+// any detail appended, so that two runs can be compared.
+func violationSet(config *Config, violations []violation) []string {
+	set := make([]string, 0, len(violations))
+	for _, v := range violations {
+		s := fmt.Sprintf("%s: %s", config.trimPath(v.file), v.bucket)
+		if v.detail != "" {
+			s += ": " + v.detail
+		}
+		set = append(set, s)
+	}
+	sort.Strings(set)
+	return set
+}
@@ -0,0 +1,113 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestViolationSet(t *testing.T) {
+	c := &Config{}
+	cur := &resultCache{Schema: cacheSchema, ConfigHash: "h", Violations: violationSet(c, []violation{
+		{file: "b.go", bucket: bucketMissing},
+		{file: "a.go", bucket: bucketForbidden, detail: `header contains "GPL"`},
+	})}
+	if want := []string{`a.go: forbidden: header contains "GPL"`, "b.go: missing"}; !reflect.DeepEqual(cur.Violations, want) {
+		t.Errorf("violationSet() = %q, want %q", cur.Violations, want)
+	}
+	if !cur.sameViolations(&resultCache{Schema: cacheSchema, ConfigHash: "h", Violations: cur.Violations}) {
+		t.Error("sameViolations(same violations) = false, want true")
+	}
+	for _, prev := range []*resultCache{
+		{},
+		{Schema: cacheSchema, ConfigHash: "other", Violations: cur.Violations},
+		{Schema: cacheSchema, ConfigHash: "h", Violations: cur.Violations[:1]},
+		{Schema: cacheSchema, ConfigHash: "h", Violations: []string{"a.go: missing", "b.go: missing"}},
+	} {
+		if cur.sameViolations(prev) {
+			t.Errorf("sameViolations(%+v) = true, want false", prev)
+		}
+	}
+}
+
+func TestResultCache(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"config.json": runConfig,
+		"tree/a.go":   "// Copyright 2026 X\npackage a\n",
+		"tree/b.go":   "package b\n",
+	})
+	cache := filepath.Join(dir, "cache.json")
+	args := []string{"-c", filepath.Join(dir, "config.json"), "-walk", filepath.Join(dir, "tree"), "-cache", cache}
+	if code, stdout, _ := runFlags(args...); code != exitViolations || stdout != "b.go\n" {
+		t.Fatalf("first run = %d, %q, want %d, %q", code, stdout, exitViolations, "b.go\n")
+	}
+	r, err := readCache(cache)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Files) != 2 || r.Files["b.go"].Bucket != bucketMissing || !reflect.DeepEqual(r.Violations, []string{"b.go: missing"}) {
+		t.Fatalf("cache = %+v, want the results of a.go and b.go", r)
+	}
+
+	// The recorded result of the unchanged a.go is reused, as wrong as
+	// it is.
+	forged := r.Files["a.go"]
+	forged.Bucket = bucketForbidden
+	r.Files["a.go"] = forged
+	if err := writeCache(cache, r); err != nil {
+		t.Fatal(err)
+	}
+	if code, stdout, _ := runFlags(args...); code != exitViolations || stdout != "a.go\nb.go\n" {
+		t.Errorf("run with a recorded result = %d, %q, want %d, %q", code, stdout, exitViolations, "a.go\nb.go\n")
+	}
+
+	// Changing a.go checks it again.
+	writeTree(t, dir, map[string]string{"tree/a.go": "// Copyright 2027 X\npackage a\n"})
+	if code, stdout, _ := runFlags(args...); code != exitViolations || stdout != "b.go\n" {
+		t.Errorf("run with a changed file = %d, %q, want %d, %q", code, stdout, exitViolations, "b.go\n")
+	}
+}
+
+func TestQuietUnlessChanged(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"config.json": runConfig,
+		"tree/a.go":   "package a\n",
+	})
+	cache := filepath.Join(dir, "cache.json")
+	args := []string{"-c", filepath.Join(dir, "config.json"), "-walk", filepath.Join(dir, "tree"), "-cache", cache, "-quiet-unless-changed"}
+	for _, tt := range []struct {
+		name   string
+		add    string
+		code   int
+		stdout string
+	}{
+		{name: "first", code: exitViolations, stdout: "a.go\n"},
+		{name: "unchanged", code: exitOK},
+		{name: "new violation", add: "b.go", code: exitViolations, stdout: "a.go\nb.go\n"},
+		{name: "unchanged again", code: exitOK},
+	} {
+		if tt.add != "" {
+			writeTree(t, dir, map[string]string{filepath.Join("tree", tt.add): "package b\n"})
+		}
+		if code, stdout, _ := runFlags(args...); code != tt.code || stdout != tt.stdout {
+			t.Errorf("%s run = %d, %q, want %d, %q", tt.name, code, stdout, tt.code, tt.stdout)
+		}
+	}
+
+	os.Remove(cache)
+	for _, args := range [][]string{
+		{"-quiet-unless-changed"},
+		{"-quiet-unless-changed", "-cache", cache, "-stream"},
+	} {
+		if code, _, _ := runFlags(append([]string{"-c", filepath.Join(dir, "config.json")}, args...)...); code != exitUsage {
+			t.Errorf("run(%q) = %d, want %d", args, code, exitUsage)
+		}
+	}
+}
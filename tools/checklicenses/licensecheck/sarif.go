@@ -0,0 +1,151 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// bucketHelp describes each bucket, as a rule of the SARIF report.
+var bucketHelp = map[bucket]string{
+	bucketMissing:          "The file carries none of the configured licenses.",
+	bucketMisplaced:        "The Go file carries its license after the package clause.",
+	bucketForbidden:        "The file carries a forbidden license.",
+	bucketFormatting:       "The license header is not followed by exactly one blank line.",
+	bucketCommentStyle:     "The license header is written in the wrong form of comment.",
+	bucketSPDXMismatch:     "The SPDX-License-Identifier contradicts the license text.",
+	bucketSPDXTag:          "The SPDX-License-Identifier is missing or names a license which is not allowed.",
+	bucketMissingReference: "The license header lacks the text its license requires.",
+	bucketShortHeader:      "The license header is shorter than its license requires, and likely truncated.",
+	bucketAmbiguous:        "The file matches more than one of the configured licenses.",
+	bucketDuplicate:        "The license header is repeated.",
+	bucketConflicting:      "The file carries more than one license header.",
+	bucketWrongHolder:      "The copyright holder is not the configured owner.",
+	bucketYearRange:        "The copyright year is out of the allowed range.",
+	bucketStaleYear:        "The copyright years do not span the last commit to the file.",
+	bucketAddedUnlicensed:  "The file was added without a license.",
+	bucketVendorUnlicensed: "The vendored file carries no recognizable license.",
+	bucketDistMissing:      "A required distribution file, like LICENSE, is missing.",
+	bucketDistContent:      "The distribution file lacks the required license.",
+	bucketDirMissing:       "The directory lacks the files its rule requires.",
+}
+
+// sarifLog is a SARIF 2.1.0 log, with only the properties the report uses.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID              string            `json:"ruleId"`
+	RuleIndex           int               `json:"ruleIndex"`
+	Level               string            `json:"level"`
+	Message             sarifMessage      `json:"message"`
+	Locations           []sarifLocation   `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// writeSARIF writes the violations to w as a SARIF log, as of time now. Each
+// bucket is a rule. Violations are located at their line, or else at the
+// first line of the file, where its license header belongs; those of
+// directories have no region.
+func writeSARIF(w io.Writer, config *Config, violations []violation, now time.Time) error {
+	driver := sarifDriver{Name: "checklicenses", InformationURI: "https://github.com/u-root/u-root/tree/main/tools/checklicenses"}
+	index := map[bucket]int{}
+	for i, b := range buckets {
+		index[b] = i
+		driver.Rules = append(driver.Rules, sarifRule{
+			ID:                   string(b),
+			ShortDescription:     sarifMessage{bucketHelp[b]},
+			DefaultConfiguration: sarifConfiguration{config.severity(b)},
+		})
+	}
+	results := []sarifResult{}
+	for _, v := range violations {
+		msg := bucketHelp[v.bucket]
+		if v.detail != "" {
+			msg = fmt.Sprintf("%s (%s)", msg, v.detail)
+		}
+		var region *sarifRegion
+		if !v.dirLevel() {
+			region = &sarifRegion{StartLine: 1}
+			if v.line > 0 {
+				region.StartLine = v.line
+			}
+		}
+		results = append(results, sarifResult{
+			RuleID:    string(v.bucket),
+			RuleIndex: index[v.bucket],
+			Level:     config.reportSeverity(v, now),
+			Message:   sarifMessage{msg},
+			Locations: []sarifLocation{{sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{displayPath(config, v.file)},
+				Region:           region,
+			}}},
+			PartialFingerprints: map[string]string{"checklicenses/v1": config.fingerprint(v)},
+		})
+	}
+	report := sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{{Tool: sarifTool{driver}, Results: results}},
+	}
+	b, err := json.MarshalIndent(report, "", "\t")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", b)
+	return err
+}
@@ -0,0 +1,80 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestBucketHelp(t *testing.T) {
+	for _, b := range buckets {
+		if bucketHelp[b] == "" {
+			t.Errorf("bucket %s has no bucketHelp", b)
+		}
+	}
+}
+
+func TestWriteSARIF(t *testing.T) {
+	c := &Config{}
+	if err := c.CompileRegexps(); err != nil {
+		t.Fatal(err)
+	}
+	v := violation{file: "a.go", bucket: bucketYearRange, detail: "1999"}
+	var b bytes.Buffer
+	if err := writeReport(&b, "sarif", c, []violation{v}, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	var got sarifLog
+	if err := json.Unmarshal(b.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Version != "2.1.0" || len(got.Runs) != 1 {
+		t.Fatalf("writeReport(sarif) = %+v, want one SARIF 2.1.0 run", got)
+	}
+	run := got.Runs[0]
+	if len(run.Tool.Driver.Rules) != len(buckets) || len(run.Results) != 1 {
+		t.Fatalf("writeReport(sarif) has %d rules and %d results, want %d and 1", len(run.Tool.Driver.Rules), len(run.Results), len(buckets))
+	}
+	r := run.Results[0]
+	if rule := run.Tool.Driver.Rules[r.RuleIndex]; rule.ID != r.RuleID || r.RuleID != string(bucketYearRange) {
+		t.Errorf("result rule %s at index %d, which is rule %s", r.RuleID, r.RuleIndex, rule.ID)
+	}
+	if r.Level != severityError || r.Locations[0].PhysicalLocation.ArtifactLocation.URI != "a.go" || r.PartialFingerprints["checklicenses/v1"] != c.fingerprint(v) {
+		t.Errorf("result = %+v", r)
+	}
+	if want := bucketHelp[bucketYearRange] + " (1999)"; r.Message.Text != want {
+		t.Errorf("message = %q, want %q", r.Message.Text, want)
+	}
+}
+
+func TestSARIFRegion(t *testing.T) {
+	c := &Config{}
+	if err := c.CompileRegexps(); err != nil {
+		t.Fatal(err)
+	}
+	violations := []violation{
+		{file: "a.go", bucket: bucketMissing},
+		{file: "b.go", bucket: bucketMisplaced, line: 6},
+		{file: "lib", bucket: bucketDirMissing},
+		{file: "LICENSE", bucket: bucketDistMissing},
+	}
+	var b bytes.Buffer
+	if err := writeReport(&b, "sarif", c, violations, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	var got sarifLog
+	if err := json.Unmarshal(b.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []*sarifRegion{{StartLine: 1}, {StartLine: 6}, nil, nil} {
+		region := got.Runs[0].Results[i].Locations[0].PhysicalLocation.Region
+		if (region == nil) != (want == nil) || region != nil && *region != *want {
+			t.Errorf("region of %+v = %+v, want %+v", violations[i], region, want)
+		}
+	}
+}
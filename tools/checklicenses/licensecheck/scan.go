@@ -0,0 +1,401 @@
+// Copyright 2017-2018 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+var generated = regexp.MustCompilePOSIX(`^// Code generated .* DO NOT EDIT\.$`)
+
+// candidate is a file selected for checking, with the configuration which
+// governs it.
+type candidate struct {
+	file   string
+	config *Config
+	// vendored candidates are third-party code, which only needs to
+	// carry some recognizable license.
+	vendored bool
+	// frontMatter candidates are documents governed by FrontMatter.
+	frontMatter bool
+	// firstYear and lastYear, if not 0, are the years of the first and
+	// last commits to the file, which the copyright years of its header
+	// must span, with -git-years.
+	firstYear, lastYear int
+	// addedIn, if not "", is the commit which added the file, whose
+	// contents there must carry a license too, with -check-first-commit.
+	// history reads them.
+	addedIn string
+	history *blobReader
+	// blobs, if not nil, reads the contents from git rather than from the
+	// working tree.
+	blobs *blobReader
+	// opts are how the contents are read and checked.
+	opts Options
+	// cacheKey, if not "", hashes everything but the contents which the
+	// result depends on, with -cache. cached is the result recorded by the
+	// last run, if any, reused if the contents did not change.
+	cacheKey string
+	cached   *cachedResult
+}
+
+// intersect returns the files which are also in other, in their original
+// order.
+func intersect(files, other []string) []string {
+	in := make(map[string]bool, len(other))
+	for _, f := range other {
+		in[f] = true
+	}
+	var both []string
+	for _, f := range files {
+		if in[f] {
+			both = append(both, f)
+		}
+	}
+	return both
+}
+
+// errScanTimeout is returned when checking a file takes longer than the
+// -file-timeout.
+var errScanTimeout = errors.New("scan timed out")
+
+// errDecompress is returned for -decompress files which are not valid gzip.
+var errDecompress = errors.New("cannot decompress")
+
+// fileFailed reports whether err means that checking one file failed, as
+// opposed to an error which should stop the run.
+func fileFailed(err error) bool {
+	return errors.Is(err, errScanTimeout) || errors.Is(err, errDecompress)
+}
+
+// gunzip returns the decompressed contents of a gzip file.
+func gunzip(contents []byte) ([]byte, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(contents))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	return io.ReadAll(zr)
+}
+
+// backgroundChecks counts the checks run by checkTimeout which have not
+// returned yet, including the abandoned ones, so that tests can wait for them.
+var backgroundChecks sync.WaitGroup
+
+// checkTimeout is like check, but gives up after timeout so that one
+// pathological file cannot stall the whole run. The check of a timed out
+// file is abandoned in the background, as a blocked read cannot be
+// interrupted; it holds on to that file and to at most the contents read
+// within the ReadLimit until the read returns or the process exits. A
+// zero timeout waits forever.
+func (cd candidate) checkTimeout(ctx context.Context, timeout time.Duration) (violation, error) {
+	if timeout <= 0 {
+		return cd.check()
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	type result struct {
+		v   violation
+		err error
+	}
+	ch := make(chan result, 1)
+	backgroundChecks.Add(1)
+	go func() {
+		defer backgroundChecks.Done()
+		v, err := cd.check()
+		ch <- result{v, err}
+	}()
+	select {
+	case r := <-ch:
+		return r.v, r.err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return violation{}, fmt.Errorf("%s: %w after %v", cd.file, errScanTimeout, timeout)
+		}
+		return violation{}, ctx.Err()
+	}
+}
+
+// check returns the violation in the candidate. Its bucket is "" if the
+// candidate conforms.
+func (cd candidate) check() (violation, error) {
+	name, contents, err := cd.read()
+	if err != nil || contents == nil {
+		return violation{file: cd.file}, err
+	}
+	var key string
+	if cd.cacheKey != "" {
+		key = resultKey(cd.cacheKey, name, contents)
+		if cd.cached != nil && cd.cached.Key == key {
+			return cd.cached.violation(cd.file), nil
+		}
+	}
+	v := cd.classify(name, contents)
+	v.resultKey = key
+	if v.bucket == "" && cd.addedIn != "" && !cd.vendored && !cd.frontMatter {
+		return cd.checkAdded(v)
+	}
+	return v, nil
+}
+
+// classify returns the violation in contents, which were read from the
+// candidate and are checked as the file name.
+func (cd candidate) classify(name string, contents []byte) violation {
+	v := violation{file: cd.file}
+	if v.skipped = cd.config.contentRejected(contents); v.skipped != "" {
+		return v
+	}
+	if cd.opts.logMasked {
+		_, applied := cd.config.maskVolatile(contents)
+		for _, i := range applied {
+			log.Printf("%s: masked VolatilePatterns[%d] %q", cd.file, i, cd.config.VolatilePatterns[i])
+		}
+	}
+	if cd.vendored {
+		v.bucket = cd.config.checkVendored(contents)
+		return v
+	}
+	if cd.frontMatter {
+		if b, ok := cd.config.checkFrontMatter(contents); ok {
+			v.bucket = b
+			return v
+		}
+	}
+	v.bucket = cd.config.checkContents(name, contents)
+	if cd.opts.countLicenses {
+		v.carries, v.version, _ = cd.config.carried(name, contents)
+	}
+	if v.bucket == "" && cd.opts.StrictSingleLicense {
+		if ids := cd.config.matchingLicenses(cd.config.headers(name, contents)); len(ids) > 1 {
+			v.bucket, v.detail = bucketAmbiguous, "matches each of "+strings.Join(ids, ", ")
+		}
+	}
+	if v.bucket == "" && cd.lastYear != 0 && cd.config.staleYears(name, contents, cd.firstYear, cd.lastYear) {
+		v.bucket, v.detail = bucketStaleYear, fmt.Sprintf("last changed in %d", cd.lastYear)
+		if cd.firstYear != 0 && cd.firstYear != cd.lastYear {
+			v.detail = fmt.Sprintf("changed from %d to %d", cd.firstYear, cd.lastYear)
+		}
+	}
+	if v.bucket == bucketSPDXTag {
+		v.detail = cd.config.spdxTagProblem(cd.config.headerRegion(contents))
+	}
+	if v.bucket == bucketShortHeader {
+		if i := cd.config.matchLicense(cd.config.headers(name, contents)); i >= 0 {
+			v.license = cd.config.Licenses[i].id("Licenses", i)
+			v.detail = cd.config.shortHeader(i, name, contents)
+		}
+	}
+	if v.bucket == bucketMisplaced {
+		v.detail, v.line, _ = cd.config.misplacedHeader(name, contents, len(cd.config.headerRegion(contents)))
+	}
+	if v.bucket == bucketMissing && cd.opts.ShowMismatch {
+		if m, ok := cd.config.firstMismatch(name, contents); ok {
+			v.closest, v.detail, v.line = m.license, m.String(), m.line
+		}
+	}
+	if v.bucket == bucketDuplicate {
+		v.line = cd.config.duplicateLine(name, contents)
+	}
+	if v.bucket == bucketForbidden {
+		if l, i := cd.config.forbiddenLicense(name, contents); l != nil {
+			v.license, v.enforceAfter = l.id("Forbidden", i), l.enforceAfter
+		} else if i := cd.config.matchLicense(cd.config.headers(name, contents)); i >= 0 {
+			// The license carries a phrase it MustNotContain.
+			v.license = cd.config.Licenses[i].id("Licenses", i)
+			v.detail = cd.config.restriction(i, name, contents)
+		}
+	}
+	return v
+}
+
+// checkFile returns the bucket of the violation in file, or "" if it carries
+// an acceptable license. Directories and generated files trivially pass.
+func (c *Config) checkFile(file string, opts Options) (bucket, error) {
+	name, contents, err := opts.readContents(file)
+	if err != nil || contents == nil {
+		return "", err
+	}
+	return c.checkContents(name, contents), nil
+}
+
+// read returns the contents of the candidate, and the name they should be
+// checked as. Files missing from the git revision read by blobs are read
+// from the working tree.
+func (cd candidate) read() (string, []byte, error) {
+	if cd.blobs == nil {
+		return cd.opts.readContents(cd.file)
+	}
+	contents, err := cd.blobs.read(cd.file)
+	if errors.Is(err, errNoBlob) {
+		return cd.opts.readContents(cd.file)
+	}
+	if err != nil || contents == nil {
+		return "", nil, err
+	}
+	if limit := cd.opts.limitFor(cd.file); limit > 0 && int64(len(contents)) > limit {
+		contents = contents[:limit]
+	}
+	return cd.opts.decode(cd.file, contents)
+}
+
+// Options are how Scan and Check read and check files. The zero Options
+// read files whole, one per CPU, without a timeout.
+type Options struct {
+	// Jobs is how many files are checked concurrently, or 0 for the
+	// number of CPUs.
+	Jobs int
+	// FileTimeout, if not 0, is how long checking one file may take
+	// before it fails with an error.
+	FileTimeout time.Duration
+	// ReadLimit is how many bytes of each file are read, where headers
+	// are, or 0 to read files whole.
+	ReadLimit int64
+	// Decompress checks the decompressed contents of .gz files.
+	Decompress bool
+	// StrictSingleLicense reports files matching more than one of the
+	// licenses as ambiguous.
+	StrictSingleLicense bool
+	// ShowMismatch details, for files missing a license, the first line
+	// where the header departs from the most similar license.
+	ShowMismatch bool
+
+	// logMasked logs the VolatilePatterns masked in each file, for -v.
+	logMasked bool
+	// countLicenses records the license each file carries, for the
+	// reports counting them.
+	countLicenses bool
+}
+
+// limitFor returns how many bytes of file are read, or 0 to read it whole.
+// Compressed files are read whole, since a prefix does not decompress.
+func (o Options) limitFor(file string) int64 {
+	if o.Decompress && path.Ext(file) == ".gz" {
+		return 0
+	}
+	return o.ReadLimit
+}
+
+// readContents returns the contents of file, and the name they should be
+// checked as. It returns nil contents for directories.
+func (o Options) readContents(file string) (string, []byte, error) {
+	// Make sure it is not a directory.
+	info, err := os.Stat(file)
+	if err != nil {
+		return "", nil, fmt.Errorf("cannot stat %s: %v", file, err)
+	}
+	if info.IsDir() {
+		return "", nil, nil
+	}
+
+	// Read from the file.
+	r, err := os.Open(file)
+	if err != nil {
+		return "", nil, fmt.Errorf("cannot open %s: %v", file, err)
+	}
+	defer r.Close()
+	var in io.Reader = r
+	if limit := o.limitFor(file); limit > 0 {
+		in = io.LimitReader(r, limit)
+	}
+	contents, err := io.ReadAll(in)
+	if err != nil {
+		return "", nil, fmt.Errorf("cannot read %s: %v", file, err)
+	}
+	return o.decode(file, contents)
+}
+
+// decode returns the contents of file as they should be checked, and the
+// name they should be checked as.
+func (o Options) decode(file string, contents []byte) (string, []byte, error) {
+	if contents == nil {
+		contents = []byte{}
+	}
+	if o.Decompress && path.Ext(file) == ".gz" {
+		var err error
+		if contents, err = gunzip(contents); err != nil {
+			return "", nil, fmt.Errorf("%s: %w: %v", file, errDecompress, err)
+		}
+		// Check the contents as the file they decompress to.
+		file = strings.TrimSuffix(file, ".gz")
+	}
+	return file, contents, nil
+}
+
+// checkContents returns the bucket of the violation in the contents of file,
+// or "" if they conform.
+func (c *Config) checkContents(file string, contents []byte) bucket {
+	// License check only makes sense for human authored code.
+	// We should skip the license check if the code is generated by
+	// a tool.
+	// https://golang.org/s/generatedcode
+	if generated.Match(contents) {
+		return ""
+	}
+	full := contents
+	contents = c.headerRegion(contents)
+	if c.forbidden(file, contents) {
+		return bucketForbidden
+	}
+	i := -1
+	if c.SPDXMode != spdxModeOnly {
+		i = c.matchLicense(c.headers(file, contents))
+	}
+	if i < 0 && !c.licenseURL(file, contents) && !c.spdxLicensed(contents) {
+		if _, _, ok := c.misplacedHeader(file, full, len(contents)); ok {
+			return bucketMisplaced
+		}
+		return bucketMissing
+	}
+	if i >= 0 && c.restriction(i, file, contents) != "" {
+		return bucketForbidden
+	}
+	if c.spdxTagProblem(contents) != "" {
+		return bucketSPDXTag
+	}
+	if i >= 0 && c.spdxMismatch(i, contents) {
+		return bucketSPDXMismatch
+	}
+	if i >= 0 && c.missingReference(i, file, contents) {
+		return bucketMissingReference
+	}
+	if i >= 0 && c.shortHeader(i, file, contents) != "" {
+		return bucketShortHeader
+	}
+	if c.duplicateHeader(file, full) {
+		return bucketDuplicate
+	}
+	if c.conflictingHeaders(file, contents) {
+		return bucketConflicting
+	}
+	if c.wrongHolder(file, contents) {
+		return bucketWrongHolder
+	}
+	if c.yearOutOfRange(file, contents) {
+		return bucketYearRange
+	}
+	if c.wrongCommentForm(file, contents) {
+		return bucketCommentStyle
+	}
+	if c.BlankLineAfterHeader {
+		if style, ok := c.styleFor(file, full); ok {
+			if end, ok := style.headerEnd(full); ok && blankLines(full[end:]) != 1 {
+				return bucketFormatting
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,102 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// blockingFIFO returns a FIFO which blocks reading it until the test is
+// done, then reads as empty. The test ends once the checks reading it do.
+func blockingFIFO(t *testing.T, name string) string {
+	fifo := filepath.Join(t.TempDir(), name)
+	if err := syscall.Mkfifo(fifo, 0o600); err != nil {
+		t.Skipf("mkfifo: %v", err)
+	}
+	// Holding a writer open makes readers block in read rather than in
+	// open, where closing it reliably unblocks them.
+	w, err := os.OpenFile(fifo, os.O_RDWR, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		// Checks not reading yet fail to find it instead.
+		os.Remove(fifo)
+		w.Close()
+		// Abandoned checks would otherwise outlive the test.
+		backgroundChecks.Wait()
+	})
+	return fifo
+}
+
+func TestCheckFileTimeout(t *testing.T) {
+	cd := candidate{file: blockingFIFO(t, "fifo.go"), config: &Config{}}
+	if _, err := cd.checkTimeout(context.Background(), 10*time.Millisecond); !errors.Is(err, errScanTimeout) {
+		t.Errorf("checkTimeout(fifo) = %v, want %v", err, errScanTimeout)
+	}
+}
+
+func TestDecompress(t *testing.T) {
+	opts := Options{Decompress: true}
+	c := &Config{Licenses: []License{{Lines: []string{"^// Copyright"}}}}
+	if err := c.CompileRegexps(); err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	write := func(name, contents string, compress bool) string {
+		name = filepath.Join(dir, name)
+		var b bytes.Buffer
+		if compress {
+			zw := gzip.NewWriter(&b)
+			zw.Write([]byte(contents))
+			zw.Close()
+		} else {
+			b.WriteString(contents)
+		}
+		if err := os.WriteFile(name, b.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return name
+	}
+	if b, err := c.checkFile(write("good.go.gz", "// Copyright X\n", true), opts); b != "" || err != nil {
+		t.Errorf("checkFile(good.go.gz) = %q, %v, want no violation", b, err)
+	}
+	if b, err := c.checkFile(write("bad.go.gz", "package a\n", true), opts); b != bucketMissing || err != nil {
+		t.Errorf("checkFile(bad.go.gz) = %q, %v, want %q", b, err, bucketMissing)
+	}
+	if _, err := c.checkFile(write("plain.go.gz", "// Copyright X\n", false), opts); !fileFailed(err) {
+		t.Errorf("checkFile(plain.go.gz) = %v, want %v", err, errDecompress)
+	}
+}
+
+func TestReadLimit(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "a.go")
+	contents := "// Copyright X\npackage a\n" + strings.Repeat("\n", 4<<10)
+	if err := os.WriteFile(file, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		kib  int
+		want int
+	}{
+		{kib: 0, want: len(contents)},
+		{kib: 1, want: 1 << 10},
+		{kib: 64, want: len(contents)},
+	} {
+		_, got, err := Options{ReadLimit: int64(tt.kib) << 10}.readContents(file)
+		if err != nil || len(got) != tt.want {
+			t.Errorf("-read-kib %d: readContents() read %d bytes, %v, want %d", tt.kib, len(got), err, tt.want)
+		}
+	}
+}
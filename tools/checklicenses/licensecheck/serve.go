@@ -0,0 +1,115 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// maxServeRequest bounds the size of a -serve request.
+const maxServeRequest = 16 << 20
+
+// serveRequest asks -serve to check a file. Without Contents, the file is
+// read from the working tree.
+type serveRequest struct {
+	Path     string  `json:"path"`
+	Contents *string `json:"contents,omitempty"`
+}
+
+// serveResult is the answer of -serve to a serveRequest.
+type serveResult struct {
+	File     string `json:"file"`
+	Selected bool   `json:"selected"`
+	Bucket   string `json:"bucket,omitempty"`
+	License  string `json:"license,omitempty"`
+	Closest  string `json:"closest,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+	Skipped  bool   `json:"skipped,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// checkHandler serves POST requests to check a file, with a serveRequest as
+// the body, and answers with a serveResult.
+type checkHandler struct {
+	cache *configCache
+}
+
+func (h checkHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "want POST", http.StatusMethodNotAllowed)
+		return
+	}
+	var req serveRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxServeRequest)).Decode(&req); err != nil {
+		http.Error(w, "invalid request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Path == "" {
+		http.Error(w, "invalid request: no path", http.StatusBadRequest)
+		return
+	}
+	var contents []byte
+	if req.Contents != nil {
+		contents = []byte(*req.Contents)
+	}
+	res, selected, err := h.cache.check(req.Path, contents)
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newServeResult(req.Path, res, selected, err)); err != nil {
+		log.Printf("-serve: %v", err)
+	}
+}
+
+// newServeResult returns the serveResult of checking file, given what
+// configCache.check returned.
+func newServeResult(file string, res FileResult, selected bool, err error) serveResult {
+	result := serveResult{File: file, Selected: selected}
+	switch {
+	case err != nil:
+		result.Error = err.Error()
+	case res.Err != nil:
+		result.Error = res.Err.Error()
+	default:
+		result.Bucket, result.License, result.Closest, result.Detail, result.Skipped = res.Bucket, res.License, res.Closest, res.Detail, res.Skipped
+	}
+	return result
+}
+
+// listen listens on addr, a TCP address like "localhost:8080", or a Unix
+// socket like "unix:/tmp/checklicenses.sock".
+func listen(addr string) (net.Listener, error) {
+	if strings.HasPrefix(addr, "unix:") {
+		return net.Listen("unix", strings.TrimPrefix(addr, "unix:"))
+	}
+	return net.Listen("tcp", addr)
+}
+
+// serve answers requests to check files under config with opts at /check on
+// addr, until ctx is done.
+func serve(ctx context.Context, addr string, config *Config, opts Options) error {
+	l, err := listen(addr)
+	if err != nil {
+		return err
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/check", checkHandler{newConfigCache(config, opts)})
+	srv := &http.Server{Handler: mux}
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+	log.Printf("serving on %s", l.Addr())
+	if err := srv.Serve(l); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestServe(t *testing.T) {
+	c := &Config{Licenses: []License{{Lines: []string{"^// Copyright"}}}, Accept: []string{`.*\.go`}}
+	if err := c.CompileRegexps(); err != nil {
+		t.Fatal(err)
+	}
+	onDisk := filepath.Join(t.TempDir(), "disk.go")
+	if err := os.WriteFile(onDisk, []byte("// Copyright 2026 X\npackage a\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(checkHandler{newConfigCache(c, Options{})})
+	defer srv.Close()
+
+	for _, tt := range []struct {
+		name   string
+		method string
+		body   string
+		status int
+		want   serveResult
+	}{
+		{
+			name:   "posted contents",
+			body:   `{"path": "a.go", "contents": "package a\n"}`,
+			status: http.StatusOK,
+			want:   serveResult{File: "a.go", Selected: true, Bucket: string(bucketMissing)},
+		},
+		{
+			name:   "read from disk",
+			body:   `{"path": "` + onDisk + `"}`,
+			status: http.StatusOK,
+			want:   serveResult{File: onDisk, Selected: true},
+		},
+		{
+			name:   "not selected",
+			body:   `{"path": "README.md", "contents": "# x\n"}`,
+			status: http.StatusOK,
+			want:   serveResult{File: "README.md"},
+		},
+		{
+			name:   "unreadable",
+			body:   `{"path": "missing.go"}`,
+			status: http.StatusOK,
+			want:   serveResult{File: "missing.go", Selected: true, Error: "cannot stat missing.go: stat missing.go: no such file or directory"},
+		},
+		{name: "no path", body: `{}`, status: http.StatusBadRequest},
+		{name: "invalid JSON", body: `{`, status: http.StatusBadRequest},
+		{name: "GET", method: http.MethodGet, status: http.StatusMethodNotAllowed},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			method := tt.method
+			if method == "" {
+				method = http.MethodPost
+			}
+			req, err := http.NewRequest(method, srv.URL, strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != tt.status {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.status)
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+			var got serveResult
+			if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("result = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
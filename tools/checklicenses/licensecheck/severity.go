@@ -0,0 +1,77 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severities of violations in machine-readable reports, as in SARIF.
+const (
+	severityError   = "error"
+	severityWarning = "warning"
+	severityNote    = "note"
+)
+
+// defaultSeverity is the severity of each bucket unless configured
+// otherwise.
+var defaultSeverity = map[bucket]string{
+	bucketMissing:          severityError,
+	bucketMisplaced:        severityError,
+	bucketForbidden:        severityError,
+	bucketFormatting:       severityNote,
+	bucketCommentStyle:     severityNote,
+	bucketSPDXMismatch:     severityError,
+	bucketSPDXTag:          severityError,
+	bucketMissingReference: severityError,
+	bucketShortHeader:      severityError,
+	bucketAmbiguous:        severityWarning,
+	bucketDuplicate:        severityNote,
+	bucketConflicting:      severityWarning,
+	bucketWrongHolder:      severityWarning,
+	bucketYearRange:        severityError,
+	bucketStaleYear:        severityWarning,
+	bucketAddedUnlicensed:  severityError,
+	bucketVendorUnlicensed: severityWarning,
+	bucketDistMissing:      severityError,
+	bucketDistContent:      severityError,
+	bucketDirMissing:       severityWarning,
+}
+
+// checkSeverities returns an error if m maps an unknown bucket or to an
+// unknown severity.
+func checkSeverities(m map[bucket]string) error {
+	for b, s := range m {
+		if _, ok := defaultSeverity[b]; !ok {
+			return fmt.Errorf("unknown bucket %q, want one of %v", b, buckets)
+		}
+		if s != severityError && s != severityWarning && s != severityNote {
+			return fmt.Errorf("unknown severity %q for %s, want %s, %s or %s", s, b, severityError, severityWarning, severityNote)
+		}
+	}
+	return nil
+}
+
+// parseSeverities parses a comma separated list of bucket=severity pairs.
+func parseSeverities(s string) (map[bucket]string, error) {
+	m := map[bucket]string{}
+	for _, pair := range strings.Split(s, ",") {
+		i := strings.IndexByte(pair, '=')
+		if i < 0 {
+			return nil, fmt.Errorf("invalid severity %q, want bucket=severity", pair)
+		}
+		m[bucket(strings.TrimSpace(pair[:i]))] = strings.TrimSpace(pair[i+1:])
+	}
+	return m, checkSeverities(m)
+}
+
+// severity returns the severity of violations in b.
+func (c *Config) severity(b bucket) string {
+	if s, ok := c.Severity[b]; ok {
+		return s
+	}
+	return defaultSeverity[b]
+}
@@ -0,0 +1,36 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import "testing"
+
+func TestSeverity(t *testing.T) {
+	for _, b := range buckets {
+		if _, ok := defaultSeverity[b]; !ok {
+			t.Errorf("bucket %s has no default severity", b)
+		}
+	}
+
+	m, err := parseSeverities("formatting=warning, missing = note")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Config{Severity: m}
+	for b, want := range map[bucket]string{
+		bucketFormatting: severityWarning,
+		bucketMissing:    severityNote,
+		bucketForbidden:  severityError,
+	} {
+		if got := c.severity(b); got != want {
+			t.Errorf("severity(%s) = %q, want %q", b, got, want)
+		}
+	}
+
+	for _, s := range []string{"formatting", "bogus=note", "missing=fatal"} {
+		if _, err := parseSeverities(s); err == nil {
+			t.Errorf("parseSeverities(%q) succeeded, want error", s)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// spdxLine matches an SPDX-License-Identifier tag and captures its license
+// expression.
+var spdxLine = regexp.MustCompile(`(?m)SPDX-License-Identifier:[ \t]*(.*?)[ \t]*(?:\*/|-->)?[ \t]*$`)
+
+// spdxTag returns the license expression of the first SPDX tag in contents,
+// and false if there is none.
+func spdxTag(contents []byte) (string, bool) {
+	m := spdxLine.FindSubmatch(contents)
+	if m == nil || len(m[1]) == 0 {
+		return "", false
+	}
+	return string(m[1]), true
+}
+
+// spdxMismatch reports whether contents carry an SPDX tag contradicting the
+// SPDX id of Licenses[i], which their text matched. Licenses without an
+// SPDX id never mismatch.
+func (c *Config) spdxMismatch(i int, contents []byte) bool {
+	want := c.Licenses[i].SPDX
+	if want == "" {
+		return false
+	}
+	tag, ok := spdxTag(contents)
+	// License identifiers are case-insensitive.
+	return ok && !strings.EqualFold(tag, want)
+}
+
+// The values of SPDXMode.
+const (
+	spdxModeOnly   = "spdx"
+	spdxModeEither = "either"
+	spdxModeBoth   = "both"
+)
+
+// checkSPDXMode returns an error if SPDXMode is not one of its values.
+func (c *Config) checkSPDXMode() error {
+	switch c.SPDXMode {
+	case "", spdxModeOnly, spdxModeEither, spdxModeBoth:
+		return nil
+	}
+	return fmt.Errorf("invalid SPDXMode %q, want %s, %s or %s", c.SPDXMode, spdxModeOnly, spdxModeEither, spdxModeBoth)
+}
+
+// spdxIDs returns the license and exception ids of an SPDX license
+// expression, like "MIT" and "Apache-2.0" of "(MIT OR Apache-2.0)".
+func spdxIDs(expr string) []string {
+	var ids []string
+	for _, f := range strings.Fields(strings.NewReplacer("(", " ", ")", " ").Replace(expr)) {
+		switch strings.ToUpper(f) {
+		case "AND", "OR", "WITH":
+			continue
+		}
+		ids = append(ids, f)
+	}
+	return ids
+}
+
+// spdxLicensed reports whether contents are licensed by their SPDX tag
+// alone, as SPDXMode "spdx" and "either" allow.
+func (c *Config) spdxLicensed(contents []byte) bool {
+	if c.SPDXMode != spdxModeOnly && c.SPDXMode != spdxModeEither {
+		return false
+	}
+	_, ok := spdxTag(contents)
+	return ok
+}
+
+// spdxTagProblem describes what is wrong with the SPDX tag of contents: it
+// is missing with SPDXMode "both", or it names a license which is not in
+// SPDXAllowed. It returns "" if nothing is.
+func (c *Config) spdxTagProblem(contents []byte) string {
+	tag, ok := spdxTag(contents)
+	if !ok {
+		if c.SPDXMode == spdxModeBoth {
+			return "no SPDX-License-Identifier tag"
+		}
+		return ""
+	}
+	if len(c.SPDXAllowed) == 0 {
+		return ""
+	}
+	for _, id := range spdxIDs(tag) {
+		allowed := false
+		for _, a := range c.SPDXAllowed {
+			// License identifiers are case-insensitive.
+			if strings.EqualFold(id, a) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Sprintf("SPDX tag %q names %s, which is not in SPDXAllowed", tag, id)
+		}
+	}
+	return ""
+}
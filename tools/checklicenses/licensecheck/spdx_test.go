@@ -0,0 +1,88 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import "testing"
+
+func TestSPDXTag(t *testing.T) {
+	for in, want := range map[string]string{
+		"// SPDX-License-Identifier: BSD-3-Clause\n":        "BSD-3-Clause",
+		"/* SPDX-License-Identifier: GPL-2.0 OR MIT */\n":   "GPL-2.0 OR MIT",
+		"<!-- SPDX-License-Identifier: Apache-2.0 -->\n":    "Apache-2.0",
+		"# SPDX-License-Identifier:\n":                      "",
+		"package a\n":                                       "",
+		"// a\n// SPDX-License-Identifier:  MIT  \npackage": "MIT",
+	} {
+		got, ok := spdxTag([]byte(in))
+		if got != want || ok != (want != "") {
+			t.Errorf("spdxTag(%q) = %q, %t, want %q", in, got, ok, want)
+		}
+	}
+}
+
+func TestSPDXMismatch(t *testing.T) {
+	c := &Config{Licenses: []License{
+		{Lines: []string{"^// Redistribution and use"}, SPDX: "BSD-3-Clause"},
+		{Lines: []string{"^// Licensed under the Apache License"}},
+	}}
+	if err := c.CompileRegexps(); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		in   string
+		want bucket
+	}{
+		{"// Redistribution and use\npackage a\n", ""},
+		{"// Redistribution and use\n// SPDX-License-Identifier: bsd-3-clause\npackage a\n", ""},
+		{"// Redistribution and use\n// SPDX-License-Identifier: MIT\npackage a\n", bucketSPDXMismatch},
+		{"// Licensed under the Apache License\n// SPDX-License-Identifier: MIT\npackage a\n", ""},
+	} {
+		if got := c.checkContents("a.go", []byte(tt.in)); got != tt.want {
+			t.Errorf("checkContents(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSPDXMode(t *testing.T) {
+	const (
+		header = "// Copyright 2026 X\n"
+		tagged = "// SPDX-License-Identifier: BSD-3-Clause\n"
+		code   = "package a\n"
+	)
+	for _, tt := range []struct {
+		mode string
+		in   string
+		want bucket
+	}{
+		{"", header + code, ""},
+		{"", tagged + code, bucketMissing},
+		{"", header + "// SPDX-License-Identifier: GPL-2.0\n" + code, bucketSPDXTag},
+		{"spdx", tagged + code, ""},
+		{"spdx", header + code, bucketMissing},
+		{"spdx", "// SPDX-License-Identifier: (BSD-3-Clause OR MIT)\n" + code, ""},
+		{"spdx", "// SPDX-License-Identifier: Apache-2.0 WITH LLVM-exception\n" + code, bucketSPDXTag},
+		{"either", header + code, ""},
+		{"either", tagged + code, ""},
+		{"either", code, bucketMissing},
+		{"both", header + tagged + code, ""},
+		{"both", header + code, bucketSPDXTag},
+		{"both", tagged + code, bucketMissing},
+	} {
+		c := &Config{
+			Licenses:    []License{{Lines: []string{"^// Copyright"}}},
+			SPDXMode:    tt.mode,
+			SPDXAllowed: []string{"bsd-3-clause", "MIT", "Apache-2.0"},
+		}
+		if err := c.CompileRegexps(); err != nil {
+			t.Fatal(err)
+		}
+		if got := c.checkContents("a.go", []byte(tt.in)); got != tt.want {
+			t.Errorf("SPDXMode %q: checkContents(%q) = %q, want %q", tt.mode, tt.in, got, tt.want)
+		}
+	}
+	if err := (&Config{SPDXMode: "tags"}).CompileRegexps(); err == nil {
+		t.Error("CompileRegexps() with SPDXMode tags = nil, want error")
+	}
+}
@@ -0,0 +1,284 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileLister lists the files a Scan considers.
+type FileLister interface {
+	ListFiles(ctx context.Context) ([]string, error)
+}
+
+// gitLister lists the files added to the git repository.
+type gitLister struct {
+	// untracked also lists the files which are neither added nor
+	// ignored.
+	untracked bool
+}
+
+// ListFiles implements FileLister.
+func (l gitLister) ListFiles(ctx context.Context) ([]string, error) {
+	files, err := gitFiles(ctx)
+	if err != nil || !l.untracked {
+		return files, err
+	}
+	untracked, err := gitUntracked(ctx)
+	if err != nil {
+		return nil, err
+	}
+	files = append(files, untracked...)
+	sort.Strings(files)
+	return files, nil
+}
+
+// walkLister lists the files under a directory, for trees outside of git.
+type walkLister struct {
+	dir string
+	// hidden also lists the files whose name or directory starts with a
+	// dot.
+	hidden bool
+}
+
+// ListFiles implements FileLister. Like git, it lists regular files and
+// symlinks, not directories, relative to the directory, and skips the .git
+// directory of repositories, even with hidden.
+func (l walkLister) ListFiles(ctx context.Context) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(l.dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		switch {
+		case p == l.dir:
+		case d.Name() == ".git" || !l.hidden && strings.HasPrefix(d.Name(), "."):
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+		case !d.IsDir():
+			rel, err := filepath.Rel(l.dir, p)
+			if err != nil {
+				return err
+			}
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("-walk: %w", err)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// FileResult is the outcome of checking one file.
+type FileResult struct {
+	// File is the path as listed.
+	File string
+	// Bucket classifies the violation in the file, e.g. "missing". It
+	// is empty if the file conforms.
+	Bucket string
+	// License names the license the violation concerns, if any.
+	License string
+	// Closest names the license the header of a file missing one comes
+	// closest to, if it is detailed as with -show-mismatch.
+	Closest string
+	// Detail explains the violation, like the phrase which makes a
+	// license forbidden.
+	Detail string
+	// Skipped is set if the file was not checked, because its contents
+	// match one of RejectContent.
+	Skipped bool
+	// Err is set if the file could not be checked, e.g. because it
+	// could not be decompressed.
+	Err error
+}
+
+// Scan checks the files listed by lister which the configuration selects,
+// including the per-directory configurations, with opts, and calls onResult
+// with the result of each as soon as it and the files listed before it are
+// checked. Calls to onResult never overlap.
+func (c *Config) Scan(ctx context.Context, lister FileLister, opts Options, onResult func(FileResult)) error {
+	files, err := lister.ListFiles(ctx)
+	if err != nil {
+		return err
+	}
+	cc := newConfigCache(c, opts)
+	candidates, _, err := cc.selectFiles(files)
+	if err != nil {
+		return err
+	}
+	return cc.scan(ctx, candidates, func(v violation, err error) {
+		onResult(fileResult(v, err))
+	})
+}
+
+// scan implements Scan for the candidates selected by the cache, calling
+// onResult as scanCandidates does.
+func (cc *configCache) scan(ctx context.Context, candidates []candidate, onResult func(violation, error)) error {
+	jobs := cc.opts.Jobs
+	if jobs == 0 {
+		jobs = runtime.NumCPU()
+	}
+	return scanCandidates(ctx, candidates, jobs, cc.opts.FileTimeout, onResult)
+}
+
+// fileResult returns the result of checking a file with violation v.
+func fileResult(v violation, err error) FileResult {
+	return FileResult{File: v.file, Bucket: string(v.bucket), License: v.license, Closest: v.closest, Detail: v.detail, Skipped: v.skipped != "", Err: err}
+}
+
+// Check checks contents as those of file, under the configuration governing
+// file, including the per-directory configurations, with opts, and reports
+// whether it selects file for checking at all. Nil contents are read from
+// file.
+func (c *Config) Check(file string, contents []byte, opts Options) (FileResult, bool, error) {
+	return newConfigCache(c, opts).check(file, contents)
+}
+
+// check implements Check, reusing the configurations already loaded.
+func (cc *configCache) check(file string, contents []byte) (FileResult, bool, error) {
+	candidates, _, err := cc.selectFiles([]string{file})
+	if err != nil {
+		return FileResult{}, false, err
+	}
+	if len(candidates) == 0 {
+		return FileResult{File: file}, false, nil
+	}
+	cd := candidates[0]
+	if contents == nil {
+		v, err := cd.check()
+		if err != nil && !fileFailed(err) {
+			return FileResult{}, true, err
+		}
+		v.file = file
+		return fileResult(v, err), true, nil
+	}
+	name, contents, err := cd.opts.decode(file, contents)
+	if err != nil {
+		return fileResult(violation{file: file}, err), true, nil
+	}
+	return fileResult(cd.classify(name, contents), nil), true, nil
+}
+
+// selectFiles returns the files which their configurations select for
+// checking, and the number of files skipped for being in NoLicenseDirs.
+func (cc *configCache) selectFiles(files []string) ([]candidate, int, error) {
+	var candidates []candidate
+	skipped := 0
+	for _, file := range files {
+		c, err := cc.forFile(file)
+		if err != nil {
+			return nil, 0, err
+		}
+		trimmedPath := c.trimPath(c.rel(file))
+		if i := c.noLicenseDirIndex(trimmedPath); i >= 0 {
+			skipped++
+			cc.record(c, file, decision{field: "NoLicenseDirs", index: i, pattern: c.NoLicenseDirs[i]})
+			continue
+		}
+		d := c.decide(trimmedPath)
+		cc.record(c, file, d)
+		if d.included {
+			candidates = append(candidates, candidate{
+				file:        file,
+				config:      c.forAsset(trimmedPath),
+				vendored:    c.vendored(trimmedPath),
+				frontMatter: c.frontMatterDoc(trimmedPath),
+				opts:        cc.opts,
+			})
+		}
+	}
+	return candidates, skipped, nil
+}
+
+// record notes, with -trace-rules, that d decided on file under c.
+func (cc *configCache) record(c *scopedConfig, file string, d decision) {
+	if !cc.trace {
+		return
+	}
+	cc.traced = append(cc.traced, tracedRule{File: file, Config: c.dir, Included: d.included, Rule: d.String()})
+}
+
+// scanCandidates checks candidates in order, calling onResult with the
+// violation in each, whose bucket is "" if it conforms. Files which fail on
+// their own, like those taking longer than timeout, are reported with the
+// error. Other errors, and ctx being done, stop the scan; the files not
+// reported yet then have no result. Up to jobs candidates are checked
+// concurrently, but onResult is called in the order of candidates, so that
+// reports do not depend on scheduling, and calls to it never overlap, so it
+// may count results without locking.
+func scanCandidates(ctx context.Context, candidates []candidate, jobs int, timeout time.Duration, onResult func(violation, error)) error {
+	if jobs < 1 {
+		jobs = 1
+	}
+	type result struct {
+		v   violation
+		err error
+	}
+	// Each candidate has its own buffered channel, so that workers never
+	// wait for results to be reported in order.
+	results := make([]chan result, len(candidates))
+	for i := range results {
+		results[i] = make(chan result, 1)
+	}
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	next := make(chan int)
+	go func() {
+		defer close(next)
+		for i := range candidates {
+			select {
+			case next <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	for w := 0; w < jobs && w < len(candidates); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				v, err := candidates[i].checkTimeout(ctx, timeout)
+				results[i] <- result{v, err}
+			}
+		}()
+	}
+	for i, cd := range candidates {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		var r result
+		select {
+		case r = <-results[i]:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if r.err != nil && !fileFailed(r.err) {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return r.err
+		}
+		r.v.file = cd.file
+		onResult(r.v, r.err)
+	}
+	return nil
+}
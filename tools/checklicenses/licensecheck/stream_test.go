@@ -0,0 +1,190 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type sliceLister []string
+
+func (l sliceLister) ListFiles(context.Context) ([]string, error) {
+	return l, nil
+}
+
+func TestScan(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, contents string) string {
+		name = filepath.Join(dir, name)
+		if err := os.WriteFile(name, []byte(contents), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return name
+	}
+	good := write("good.go", "// Copyright 2026 X\npackage a\n")
+	bad := write("bad.go", "package a\n")
+	other := write("README.md", "# readme\n")
+
+	c := &Config{Licenses: []License{{Lines: []string{"^// Copyright"}}}, Accept: []string{".*\\.go"}}
+	if err := c.CompileRegexps(); err != nil {
+		t.Fatal(err)
+	}
+	var got []FileResult
+	if err := c.Scan(context.Background(), sliceLister{good, other, bad}, Options{}, func(r FileResult) {
+		got = append(got, r)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	want := []FileResult{
+		{File: good},
+		{File: bad, Bucket: string(bucketMissing)},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Scan() results = %+v, want %+v", got, want)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := c.Scan(ctx, sliceLister{good}, Options{}, func(FileResult) {
+		t.Error("Scan() with a canceled context produced a result")
+	}); err != context.Canceled {
+		t.Errorf("Scan() with a canceled context = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestScanOptions(t *testing.T) {
+	fifo := blockingFIFO(t, "fifo.go")
+	c := &Config{Licenses: []License{{Lines: []string{"^// Copyright"}}}, Accept: []string{".*\\.go"}}
+	if err := c.CompileRegexps(); err != nil {
+		t.Fatal(err)
+	}
+	var got []FileResult
+	opts := Options{Jobs: 2, FileTimeout: 10 * time.Millisecond}
+	if err := c.Scan(context.Background(), sliceLister{fifo}, opts, func(r FileResult) {
+		got = append(got, r)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].File != fifo || !errors.Is(got[0].Err, errScanTimeout) {
+		t.Errorf("Scan() with a FileTimeout = %+v, want %s failing with %v", got, fifo, errScanTimeout)
+	}
+
+	// ShowMismatch details the missing license, as it would the flag.
+	res, selected, err := c.Check("a.go", []byte("// Copyleft X\npackage a\n"), Options{ShowMismatch: true})
+	if err != nil || !selected || res.Bucket != string(bucketMissing) || res.Closest != "Licenses[0]" {
+		t.Errorf("Check() with ShowMismatch = %+v, %t, %v, want %q closest to Licenses[0]", res, selected, err, bucketMissing)
+	}
+}
+
+func TestScanCandidatesKeepsViolation(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "a.go")
+	if err := os.WriteFile(file, []byte("// GPL\npackage a\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	c := &Config{Forbidden: []License{{Name: "GPL", Lines: []string{"^// GPL"}, EnforceAfter: "2030-01-01"}}}
+	if err := c.CompileRegexps(); err != nil {
+		t.Fatal(err)
+	}
+	var got []violation
+	if err := scanCandidates(context.Background(), []candidate{{file: file, config: c}}, 1, 0, func(v violation, err error) {
+		if err != nil {
+			t.Error(err)
+		}
+		got = append(got, v)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	want := []violation{{file: file, bucket: bucketForbidden, license: "GPL", enforceAfter: c.Forbidden[0].enforceAfter}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("scanCandidates() violations = %+v, want %+v", got, want)
+	}
+}
+
+func TestScanCandidatesOrder(t *testing.T) {
+	dir := t.TempDir()
+	c := &Config{Licenses: []License{{Lines: []string{"^// Copyright"}}}}
+	if err := c.CompileRegexps(); err != nil {
+		t.Fatal(err)
+	}
+	var candidates []candidate
+	var want []string
+	for i := 0; i < 100; i++ {
+		file := filepath.Join(dir, fmt.Sprintf("%03d.go", i))
+		contents := "// Copyright X\npackage a\n"
+		if i%3 == 0 {
+			contents = "package a\n"
+		}
+		if err := os.WriteFile(file, []byte(contents), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		candidates = append(candidates, candidate{file: file, config: c})
+		want = append(want, file)
+	}
+	for _, jobs := range []int{1, 8} {
+		var got []string
+		missing := 0
+		if err := scanCandidates(context.Background(), candidates, jobs, 0, func(v violation, err error) {
+			if err != nil {
+				t.Error(err)
+			}
+			got = append(got, v.file)
+			if v.bucket == bucketMissing {
+				missing++
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) || missing != 34 {
+			t.Errorf("scanCandidates(jobs %d) reported %q with %d missing, want %q with 34", jobs, got, missing, want)
+		}
+	}
+}
+
+func TestWalkLister(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"b.go", "a/x.go", "a-b/y.go", ".git/config", "sub/.git", ".github/ci.yml", ".hidden.go"} {
+		file := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		dir    string
+		hidden bool
+		want   []string
+	}{
+		{".", false, []string{"a-b/y.go", "a/x.go", "b.go"}},
+		{".", true, []string{".github/ci.yml", ".hidden.go", "a-b/y.go", "a/x.go", "b.go"}},
+		{dir, false, []string{"a-b/y.go", "a/x.go", "b.go"}},
+		{"a", false, []string{"x.go"}},
+		{".github", false, []string{"ci.yml"}},
+	} {
+		got, err := walkLister{dir: tt.dir, hidden: tt.hidden}.ListFiles(context.Background())
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("walkLister{%q, hidden %t}.ListFiles() = %q, %v, want %q", tt.dir, tt.hidden, got, err, tt.want)
+		}
+	}
+	if _, err := (walkLister{dir: "missing"}).ListFiles(context.Background()); err == nil {
+		t.Error("walkLister{missing}.ListFiles() succeeded, want error")
+	}
+}
@@ -0,0 +1,216 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"runtime/debug"
+	"sort"
+	"time"
+)
+
+// summary counts what happened to the files considered by a run.
+type summary struct {
+	// listed is the number of files found before any rule was applied.
+	listed int
+	// checked is the number of files whose contents were checked.
+	checked int
+	// passed is the number of checked files carrying an acceptable
+	// license.
+	passed int
+	// errors is the number of files which could not be checked.
+	errors int
+	// violations is the number of files without an acceptable license.
+	violations int
+	// skippedDirs is the number of files skipped because they are in
+	// one of Config.NoLicenseDirs.
+	skippedDirs int
+	// skippedGenerated is the number of files skipped because
+	// .gitattributes marks them linguist-generated.
+	skippedGenerated int
+	// skippedContent is the number of files skipped because their
+	// contents match one of Config.RejectContent.
+	skippedContent int
+	// baselined is the number of violations tolerated because they are
+	// recorded in the -baseline file.
+	baselined int
+	// fixed is the number of violations fixed by -fix.
+	fixed int
+	// pending is the number of violations of rules whose EnforceAfter
+	// date has not come yet, which do not fail the run.
+	pending int
+	// buckets counts the violations which were not baselined, by bucket.
+	buckets map[bucket]int
+	// extensions counts the checked files by extension, as grouped by
+	// -diffstat.
+	extensions map[string]*extStats
+}
+
+// extStats counts the checked files with one extension.
+type extStats struct {
+	scanned, passed, failed int
+}
+
+// count records the result of checking file, which failed if it has a
+// violation.
+func (s *summary) count(file string, failed bool) {
+	if s.extensions == nil {
+		s.extensions = map[string]*extStats{}
+	}
+	e, ok := s.extensions[extension(file)]
+	if !ok {
+		e = &extStats{}
+		s.extensions[extension(file)] = e
+	}
+	e.scanned++
+	if failed {
+		e.failed++
+	} else {
+		e.passed++
+	}
+}
+
+// summaryRow is one of the counts of a summary.
+type summaryRow struct {
+	Name string
+	N    int
+}
+
+// rows returns the counts of s, in the order they are printed.
+func (s *summary) rows() []summaryRow {
+	return []summaryRow{
+		{"listed", s.listed},
+		{"checked", s.checked},
+		{"passed", s.passed},
+		{"errors", s.errors},
+		{"violations", s.violations},
+		{"skipped dirs", s.skippedDirs},
+		{"skipped generated", s.skippedGenerated},
+		{"skipped content", s.skippedContent},
+		{"baselined", s.baselined},
+		{"fixed", s.fixed},
+		{"not enforced yet", s.pending},
+	}
+}
+
+func (s *summary) print(w io.Writer) {
+	for _, row := range s.rows() {
+		fmt.Fprintf(w, "%-18s %d\n", row.Name+":", row.N)
+	}
+	for _, b := range buckets {
+		if n := s.buckets[b]; n > 0 {
+			fmt.Fprintf(w, "%-18s %d\n", "  "+string(b)+":", n)
+		}
+	}
+	if len(s.extensions) > 0 {
+		fmt.Fprintln(w, "by extension:")
+	}
+	exts := make([]string, 0, len(s.extensions))
+	for ext := range s.extensions {
+		exts = append(exts, ext)
+	}
+	sort.Strings(exts)
+	for _, ext := range exts {
+		e := s.extensions[ext]
+		fmt.Fprintf(w, "%-18s %d scanned, %d passed, %d failed\n", "  "+ext+":", e.scanned, e.passed, e.failed)
+	}
+}
+
+// summarySchema is the version of the -summary-json schema. It changes only
+// when fields are removed or change meaning, never when they are added.
+const summarySchema = 1
+
+// jsonSummary is the summary written by -summary-json, for tracking trends
+// across runs.
+type jsonSummary struct {
+	Schema     int            `json:"schema"`
+	Version    string         `json:"version"`
+	Timestamp  string         `json:"timestamp"`
+	ConfigHash string         `json:"config_hash"`
+	Listed     int            `json:"listed"`
+	Checked    int            `json:"checked"`
+	Passed     int            `json:"passed"`
+	Errors     int            `json:"errors"`
+	Violations int            `json:"violations"`
+	Baselined  int            `json:"baselined"`
+	Fixed      int            `json:"fixed"`
+	Pending    int            `json:"pending"`
+	Buckets    map[bucket]int `json:"buckets"`
+	// Coverage is the fraction of the checked files which passed.
+	Coverage float64 `json:"coverage"`
+	// Extensions counts the checked files by extension.
+	Extensions map[string]jsonExtension `json:"extensions"`
+}
+
+// jsonExtension counts the checked files with one extension in the
+// -summary-json schema.
+type jsonExtension struct {
+	Scanned int `json:"scanned"`
+	Passed  int `json:"passed"`
+	Failed  int `json:"failed"`
+}
+
+// toolVersion returns the module version checklicenses was built from, or
+// "(devel)" for a build from a working tree.
+func toolVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		return info.Main.Version
+	}
+	return "(devel)"
+}
+
+// json returns the summary of a run at time now, under the configuration
+// hash configHash, in the -summary-json schema.
+func (s *summary) json(configHash string, now time.Time) ([]byte, error) {
+	j := jsonSummary{
+		Schema:     summarySchema,
+		Version:    toolVersion(),
+		Timestamp:  now.UTC().Format(time.RFC3339),
+		ConfigHash: configHash,
+		Listed:     s.listed,
+		Checked:    s.checked,
+		Passed:     s.passed,
+		Errors:     s.errors,
+		Violations: s.violations,
+		Baselined:  s.baselined,
+		Fixed:      s.fixed,
+		Pending:    s.pending,
+		Buckets:    make(map[bucket]int, len(buckets)),
+		Coverage:   1,
+		Extensions: make(map[string]jsonExtension, len(s.extensions)),
+	}
+	// List every bucket, so that a bucket without violations reads 0
+	// rather than missing.
+	for _, b := range buckets {
+		j.Buckets[b] = s.buckets[b]
+	}
+	for ext, e := range s.extensions {
+		j.Extensions[ext] = jsonExtension{Scanned: e.scanned, Passed: e.passed, Failed: e.failed}
+	}
+	if s.checked > 0 {
+		j.Coverage = float64(s.passed) / float64(s.checked)
+	}
+	b, err := json.MarshalIndent(j, "", "\t")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+// writeJSON writes the summary of a run at time now, under the
+// configuration hash configHash, to file.
+func (s *summary) writeJSON(file, configHash string, now time.Time) error {
+	b, err := s.json(configHash, now)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(file, b, 0o644); err != nil {
+		return fmt.Errorf("-summary-json: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSummaryJSON(t *testing.T) {
+	s := &summary{
+		listed:     10,
+		checked:    8,
+		passed:     6,
+		violations: 2,
+		buckets:    map[bucket]int{bucketMissing: 2},
+	}
+	file := filepath.Join(t.TempDir(), "summary.json")
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.FixedZone("CEST", 2*3600))
+	if err := s.writeJSON(file, "abc", now); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range map[string]interface{}{
+		"schema":      float64(summarySchema),
+		"version":     "(devel)",
+		"timestamp":   "2026-10-14T10:00:00Z",
+		"config_hash": "abc",
+		"checked":     float64(8),
+		"passed":      float64(6),
+		"coverage":    0.75,
+	} {
+		if got[key] != want {
+			t.Errorf("summary %s = %v, want %v", key, got[key], want)
+		}
+	}
+	bs, _ := got["buckets"].(map[string]interface{})
+	if len(bs) != len(buckets) || bs[string(bucketMissing)] != float64(2) || bs[string(bucketForbidden)] != float64(0) {
+		t.Errorf("summary buckets = %v, want every bucket with missing 2", bs)
+	}
+}
+
+func TestSummaryExtensions(t *testing.T) {
+	results := []struct {
+		file   string
+		failed bool
+	}{
+		{"a.go", false},
+		{"b.go", true},
+		{"Makefile", false},
+		{"c.go", false},
+		{"d.sh", true},
+	}
+	var prints []string
+	for _, reverse := range []bool{false, true} {
+		s := &summary{}
+		for i := range results {
+			r := results[i]
+			if reverse {
+				r = results[len(results)-1-i]
+			}
+			s.count(r.file, r.failed)
+		}
+		if got, want := *s.extensions["*.go"], (extStats{scanned: 3, passed: 2, failed: 1}); got != want {
+			t.Errorf("extensions[*.go] = %+v, want %+v", got, want)
+		}
+		var b bytes.Buffer
+		s.print(&b)
+		prints = append(prints, b.String())
+	}
+	if prints[0] != prints[1] {
+		t.Errorf("print() depends on the order of results:\n%s\nvs\n%s", prints[0], prints[1])
+	}
+	want := "by extension:\n" +
+		"  (none):          1 scanned, 1 passed, 0 failed\n" +
+		"  *.go:            3 scanned, 2 passed, 1 failed\n" +
+		"  *.sh:            1 scanned, 0 passed, 1 failed\n"
+	if !bytes.HasSuffix([]byte(prints[0]), []byte(want)) {
+		t.Errorf("print() = %q, want suffix %q", prints[0], want)
+	}
+}
@@ -0,0 +1,4 @@
+/*! Copyright 2020 the u-root Authors. All rights reserved
+ * Use of this source code is governed by a BSD-style
+ * license that can be found in the LICENSE file.
+ */!function(e){var t={};function n(r){if(t[r])return t[r].exports}}([]);
@@ -0,0 +1,3 @@
+!function(e){var t={}}([]);/* Copyright 2020 the u-root Authors. All rights reserved
+ * Use of this source code is governed by a BSD-style
+ * license that can be found in the LICENSE file. */
@@ -0,0 +1,95 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+)
+
+// decision is the rule which decided whether a file is checked.
+type decision struct {
+	included bool
+	// field is the configuration field holding the rule, like "Reject",
+	// or "" if no rule selected the file.
+	field string
+	// index is the position of the rule in field, or -1 for the fields
+	// which are sets, like AcceptExtensions.
+	index int
+	// pattern is the rule as configured.
+	pattern string
+}
+
+func (d decision) String() string {
+	switch {
+	case d.field == "":
+		return "no Accept rule matched, not checked"
+	case d.index < 0:
+		return fmt.Sprintf("%s %q", d.field, d.pattern)
+	}
+	return fmt.Sprintf("%s[%d] %q", d.field, d.index, d.pattern)
+}
+
+// decide returns the rule selecting or excluding file, which is relative to
+// the configuration and trimmed of GoPkg, in the order included applies
+// them: a file must be accepted, by extension or pattern, and then not
+// rejected by any of RejectExtensions, Reject or Ignore.
+func (c *Config) decide(file string) decision {
+	ext := path.Ext(file)
+	d := decision{index: -1}
+	if c.acceptExts[ext] {
+		d = decision{included: true, field: "AcceptExtensions", index: -1, pattern: ext}
+	} else if i := firstRule(c.accept, file); i >= 0 {
+		d = decision{included: true, field: "Accept", index: i, pattern: c.Accept[i]}
+	}
+	if !d.included {
+		return d
+	}
+	if c.rejectExts[ext] {
+		return decision{field: "RejectExtensions", index: -1, pattern: ext}
+	}
+	if i := firstRule(c.reject, file); i >= 0 {
+		return decision{field: "Reject", index: i, pattern: c.Reject[i]}
+	}
+	if i, ok := c.ignored(file); ok {
+		return decision{field: "Ignore", index: i, pattern: c.Ignore[i]}
+	}
+	return d
+}
+
+// firstRule returns the index of the first of rules matching file, or -1.
+func firstRule(rules []rule, file string) int {
+	for i, r := range rules {
+		if r.MatchString(file) {
+			return i
+		}
+	}
+	return -1
+}
+
+// tracedRule is how -trace-rules records the decision on one file.
+type tracedRule struct {
+	File     string `json:"file"`
+	Config   string `json:"config,omitempty"`
+	Included bool   `json:"included"`
+	Rule     string `json:"rule"`
+}
+
+// writeTrace writes the decisions recorded by selectFiles to file.
+func writeTrace(file string, traced []tracedRule) error {
+	if traced == nil {
+		traced = []tracedRule{}
+	}
+	b, err := json.MarshalIndent(traced, "", "\t")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(file, append(b, '\n'), 0o644); err != nil {
+		return fmt.Errorf("-trace-rules: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import "testing"
+
+func TestTraceRules(t *testing.T) {
+	c := &Config{
+		Accept:           []string{`.*\.go`, `.*\.sh`},
+		AcceptExtensions: []string{"c"},
+		Reject:           []string{`gen/.*`},
+		Ignore:           []string{"*_test.go", "!keep_test.go", "tmp/"},
+		NoLicenseDirs:    []string{"vendor"},
+	}
+	if err := c.CompileRegexps(); err != nil {
+		t.Fatal(err)
+	}
+	cc := newConfigCache(c, Options{})
+	cc.trace = true
+	files := []string{"a.go", "b.sh", "c.c", "gen/d.go", "e_test.go", "keep_test.go", "tmp/keep_test.go", "vendor/f.go", "g.txt"}
+	if _, _, err := cc.selectFiles(files); err != nil {
+		t.Fatal(err)
+	}
+	want := []tracedRule{
+		{File: "a.go", Included: true, Rule: `Accept[0] ".*\\.go"`},
+		{File: "b.sh", Included: true, Rule: `Accept[1] ".*\\.sh"`},
+		{File: "c.c", Included: true, Rule: `AcceptExtensions ".c"`},
+		{File: "gen/d.go", Rule: `Reject[0] "gen/.*"`},
+		{File: "e_test.go", Rule: `Ignore[0] "*_test.go"`},
+		{File: "keep_test.go", Included: true, Rule: `Accept[0] ".*\\.go"`},
+		{File: "tmp/keep_test.go", Rule: `Ignore[2] "tmp/"`},
+		{File: "vendor/f.go", Rule: `NoLicenseDirs[0] "vendor"`},
+		{File: "g.txt", Rule: "no Accept rule matched, not checked"},
+	}
+	if len(cc.traced) != len(want) {
+		t.Fatalf("traced %d files, want %d: %+v", len(cc.traced), len(want), cc.traced)
+	}
+	for i := range want {
+		if cc.traced[i] != want[i] {
+			t.Errorf("traced[%d] = %+v, want %+v", i, cc.traced[i], want[i])
+		}
+	}
+}
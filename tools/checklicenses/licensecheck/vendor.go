@@ -0,0 +1,55 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+// vendorHeaderSize bounds how far into a vendored file a license is looked
+// for.
+const vendorHeaderSize = 8 << 10
+
+// knownLicense matches the telltale phrases of common open source licenses.
+var knownLicense = regexp.MustCompile(`(?i)` + strings.Join([]string{
+	`SPDX-License-Identifier:`,
+	`Licensed under the Apache License`,
+	`Apache License,? Version 2\.0`,
+	`Permission is hereby granted, free of charge`,
+	`MIT License`,
+	`Redistribution and use in source and binary forms`,
+	`Use of this source code is governed by a BSD-style`,
+	`GNU (Lesser |Library |Affero )?General Public License`,
+	`Mozilla Public License`,
+	`Permission to use, copy, modify, and(/or)? distribute this software`,
+	`Eclipse Public License`,
+	`This is free and unencumbered software released into the public domain`,
+}, "|"))
+
+// vendored reports whether file is in one of the Vendor directories.
+func (c *Config) vendored(file string) bool {
+	for _, g := range c.Vendor {
+		for dir := path.Dir(file); dir != "." && dir != "/"; dir = path.Dir(dir) {
+			if ok, _ := path.Match(g, dir); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// checkVendored returns the bucket of the violation in the contents of a
+// vendored file, or "" if it carries any recognizable license.
+func (c *Config) checkVendored(contents []byte) bucket {
+	if len(contents) > vendorHeaderSize {
+		contents = contents[:vendorHeaderSize]
+	}
+	if generated.Match(contents) || knownLicense.Match(contents) {
+		return ""
+	}
+	return bucketVendorUnlicensed
+}
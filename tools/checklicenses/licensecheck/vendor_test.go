@@ -0,0 +1,44 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import "testing"
+
+func TestVendored(t *testing.T) {
+	c := &Config{Vendor: []string{"vendor", "third_party/*"}}
+	for file, want := range map[string]bool{
+		"vendor/golang.org/x/sys/unix/a.go": true,
+		"third_party/lib/a.c":               true,
+		"third_party/a.c":                   false,
+		"cmds/core/ls/ls.go":                false,
+		"vendor.go":                         false,
+	} {
+		if got := c.vendored(file); got != want {
+			t.Errorf("vendored(%q) = %t, want %t", file, got, want)
+		}
+	}
+}
+
+func TestCheckVendored(t *testing.T) {
+	c := &Config{}
+	for _, tt := range []struct {
+		name     string
+		contents string
+		want     bucket
+	}{
+		{"spdx", "// SPDX-License-Identifier: MIT\npackage x\n", ""},
+		{"bsd", "// Use of this source code is governed by a BSD-style\npackage x\n", ""},
+		{"apache", "/*\n * Licensed under the Apache License, Version 2.0\n */\n", ""},
+		{"gpl", "# GNU General Public License v2\n", ""},
+		{"generated", "// Code generated by stringer. DO NOT EDIT.\npackage x\n", ""},
+		{"none", "package x\n", bucketVendorUnlicensed},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.checkVendored([]byte(tt.contents)); got != tt.want {
+				t.Errorf("checkVendored(%q) = %q, want %q", tt.contents, got, tt.want)
+			}
+		})
+	}
+}
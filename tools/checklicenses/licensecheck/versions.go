@@ -0,0 +1,95 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+)
+
+// carried returns the id and Version of the first of Licenses which file
+// carries, or false if it carries none.
+func (c *Config) carried(file string, contents []byte) (string, string, bool) {
+	i := c.matchLicense(c.headers(file, contents))
+	if i < 0 {
+		return "", "", false
+	}
+	return c.Licenses[i].id("Licenses", i), c.Licenses[i].Version, true
+}
+
+// licenseVersion is a license and one of its versions.
+type licenseVersion struct {
+	license, version string
+}
+
+// licenseCounts counts the checked files by the license and version they
+// carry, for -license-report and -require-all-licenses-used.
+type licenseCounts map[licenseVersion]int
+
+// add counts the file of v, which is "" if the file carries none of
+// Licenses.
+func (lc licenseCounts) add(v violation) {
+	lc[licenseVersion{v.carries, v.version}]++
+}
+
+// unused returns the ids of the Licenses of c which none of the counted
+// files carry, in order. Files count towards the first of Licenses they
+// carry only, so this includes licenses always shadowed by an earlier one.
+func (lc licenseCounts) unused(c *Config) []string {
+	used := map[string]bool{}
+	for lv := range lc {
+		used[lv.license] = true
+	}
+	var ids []string
+	for i := range c.Licenses {
+		if id := c.Licenses[i].id("Licenses", i); !used[id] {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// sorted returns the licenses and versions counted, most common first, and
+// the number of files counted.
+func (lc licenseCounts) sorted() ([]licenseVersion, int) {
+	total := 0
+	rows := make([]licenseVersion, 0, len(lc))
+	for lv, n := range lc {
+		rows = append(rows, lv)
+		total += n
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if lc[rows[i]] != lc[rows[j]] {
+			return lc[rows[i]] > lc[rows[j]]
+		}
+		if rows[i].license != rows[j].license {
+			return rows[i].license < rows[j].license
+		}
+		return rows[i].version < rows[j].version
+	})
+	return rows, total
+}
+
+// print writes the number of files by license and version, most common
+// first, with their share of the files counted.
+func (lc licenseCounts) print(w io.Writer) {
+	rows, total := lc.sorted()
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "LICENSE\tVERSION\tFILES\tSHARE")
+	for _, lv := range rows {
+		license, version := lv.license, lv.version
+		if license == "" {
+			license = "(none)"
+		}
+		if version == "" {
+			version = "-"
+		}
+		n := lc[lv]
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%.1f%%\n", license, version, n, 100*float64(n)/float64(total))
+	}
+	tw.Flush()
+}
@@ -0,0 +1,70 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestLicenseReport(t *testing.T) {
+	c := &Config{Licenses: []License{
+		{Name: "Apache", Version: "2.0", Lines: []string{"^// Apache 2.0"}},
+		{Name: "BSD", Version: "new", Lines: []string{"^// BSD, see LICENSE"}},
+		{Name: "BSD", Version: "old", Lines: []string{"^// BSD"}},
+	}}
+	if err := c.CompileRegexps(); err != nil {
+		t.Fatal(err)
+	}
+	lc := licenseCounts{}
+	for _, in := range []string{
+		"// BSD\npackage a\n",
+		"// BSD\npackage b\n",
+		"// BSD, see LICENSE\npackage c\n",
+		"// Apache 2.0\npackage d\n",
+		"// BSD\npackage e\n",
+		"package f\n",
+	} {
+		lc.add(candidate{file: "a.go", config: c, opts: Options{countLicenses: true}}.classify("a.go", []byte(in)))
+	}
+	var b bytes.Buffer
+	lc.print(&b)
+	want := `LICENSE  VERSION  FILES  SHARE
+BSD      old      3      50.0%
+(none)   -        1      16.7%
+Apache   2.0      1      16.7%
+BSD      new      1      16.7%
+`
+	if b.String() != want {
+		t.Errorf("print() =\n%s\nwant\n%s", b.String(), want)
+	}
+}
+
+func TestUnusedLicenses(t *testing.T) {
+	c := &Config{Licenses: []License{
+		{Name: "BSD", Lines: []string{"^// BSD"}},
+		{Name: "Apache", Lines: []string{"^// Apache"}},
+		{Lines: []string{"^// MIT"}},
+		{Name: "BSD, see LICENSE", Lines: []string{"^// BSD, see LICENSE"}},
+	}}
+	if err := c.CompileRegexps(); err != nil {
+		t.Fatal(err)
+	}
+	lc := licenseCounts{}
+	for _, in := range []string{
+		"// BSD\npackage a\n",
+		"// BSD, see LICENSE\npackage b\n",
+		"// MIT\npackage c\n",
+		"package d\n",
+	} {
+		lc.add(candidate{file: "a.go", config: c, opts: Options{countLicenses: true}}.classify("a.go", []byte(in)))
+	}
+	// The second BSD license is shadowed by the first.
+	want := []string{"Apache", "BSD, see LICENSE"}
+	if got := lc.unused(c); !reflect.DeepEqual(got, want) {
+		t.Errorf("unused() = %q, want %q", got, want)
+	}
+}
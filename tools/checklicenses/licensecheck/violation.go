@@ -0,0 +1,169 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// bucket classifies why a file violates the license policy.
+type bucket string
+
+const (
+	// bucketMissing files carry none of the configured licenses.
+	bucketMissing bucket = "missing"
+	// bucketMisplaced Go files carry a license, but after their package
+	// clause.
+	bucketMisplaced bucket = "misplaced-header"
+	// bucketForbidden files carry one of the Forbidden licenses.
+	bucketForbidden bucket = "forbidden"
+	// bucketFormatting files carry a license, but lay it out wrongly.
+	bucketFormatting bucket = "formatting"
+	// bucketCommentStyle files write their header in another form of
+	// comment than CommentForms requires.
+	bucketCommentStyle bucket = "comment-style"
+	// bucketSPDXMismatch files carry an SPDX-License-Identifier which
+	// contradicts the license text they carry.
+	bucketSPDXMismatch bucket = "spdx-mismatch"
+	// bucketSPDXTag files lack the SPDX-License-Identifier which
+	// SPDXMode "both" requires, or name a license outside SPDXAllowed.
+	bucketSPDXTag bucket = "spdx-tag"
+	// bucketMissingReference files carry a license, but lack the text,
+	// like a reference to a NOTICE file, which it Requires.
+	bucketMissingReference bucket = "missing-reference"
+	// bucketShortHeader files carry a license, but in a header shorter
+	// than its MinLines, which is likely truncated.
+	bucketShortHeader bucket = "short-header"
+	// bucketAmbiguous files match more than one of the configured
+	// Licenses, with -strict-single-license.
+	bucketAmbiguous bucket = "ambiguous-license"
+	// bucketDuplicate files repeat their license header, identically.
+	bucketDuplicate bucket = "duplicate-header"
+	// bucketConflicting files carry more than one license header.
+	bucketConflicting bucket = "conflicting-headers"
+	// bucketWrongHolder files name a copyright holder other than the
+	// configured Owner.
+	bucketWrongHolder bucket = "wrong-holder"
+	// bucketYearRange files claim a copyright year outside of MinYear and
+	// MaxYear.
+	bucketYearRange bucket = "year-range"
+	// bucketStaleYear files claim copyright years which do not span the
+	// years of their first and last commits, with -git-years.
+	bucketStaleYear bucket = "stale-year"
+	// bucketAddedUnlicensed files were added without a license, even if
+	// they carry one now, with -check-first-commit.
+	bucketAddedUnlicensed bucket = "added-unlicensed"
+	// bucketVendorUnlicensed vendored files carry no recognizable license.
+	bucketVendorUnlicensed bucket = "vendor-unlicensed"
+	// bucketDistMissing is reported for distribution files, like LICENSE
+	// or COPYING, which are required but absent.
+	bucketDistMissing bucket = "dist-missing"
+	// bucketDistContent distribution files lack the required license.
+	bucketDistContent bucket = "dist-content"
+	// bucketDirMissing is reported for directories lacking the files a
+	// DirRule requires.
+	bucketDirMissing bucket = "dir-missing"
+)
+
+// buckets lists every bucket, in the order they are reported.
+var buckets = []bucket{
+	bucketMissing,
+	bucketMisplaced,
+	bucketForbidden,
+	bucketFormatting,
+	bucketCommentStyle,
+	bucketSPDXMismatch,
+	bucketSPDXTag,
+	bucketMissingReference,
+	bucketShortHeader,
+	bucketAmbiguous,
+	bucketDuplicate,
+	bucketConflicting,
+	bucketWrongHolder,
+	bucketYearRange,
+	bucketStaleYear,
+	bucketAddedUnlicensed,
+	bucketVendorUnlicensed,
+	bucketDistMissing,
+	bucketDistContent,
+	bucketDirMissing,
+}
+
+// fixable reports whether -fix knows how to fix violations in b.
+func fixable(b bucket) bool {
+	return b == bucketMissing || b == bucketFormatting || b == bucketCommentStyle || b == bucketDuplicate
+}
+
+// parseBuckets parses a comma separated list of bucket names.
+func parseBuckets(s string) (map[bucket]bool, error) {
+	set := map[bucket]bool{}
+	for _, name := range strings.Split(s, ",") {
+		b := bucket(strings.TrimSpace(name))
+		known := false
+		for _, k := range buckets {
+			known = known || b == k
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown bucket %q, want one of %v", b, buckets)
+		}
+		set[b] = true
+	}
+	return set, nil
+}
+
+// dirLevel reports whether v concerns a directory or a file it lacks, rather
+// than a file there is.
+func (v violation) dirLevel() bool {
+	return v.bucket == bucketDirMissing || v.bucket == bucketDistMissing
+}
+
+// filterBuckets returns the violations in the set of buckets only, or all of
+// them if only is nil.
+func filterBuckets(violations []violation, only map[bucket]bool) []violation {
+	if only == nil {
+		return violations
+	}
+	var kept []violation
+	for _, v := range violations {
+		if only[v.bucket] {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
+
+// violation is a file which does not conform to the license policy.
+type violation struct {
+	// file is the path as listed, before GoPkg is trimmed.
+	file   string
+	bucket bucket
+	// license names the license the violation concerns, like the
+	// Forbidden license a file carries, if there is a single one.
+	license string
+	// closest names the license the header of a file missing one comes
+	// closest to, with -show-mismatch. Unlike license, it is no part of
+	// the fingerprint, which must not depend on flags.
+	closest string
+	// detail explains the violation, like the phrase a license
+	// MustNotContain which the file carries. Text reports print it after
+	// the path.
+	detail string
+	// line is the 1-based line of the file the violation is at, or 0 if
+	// it is not known.
+	line int
+	// carries and version are the id and Version of the license the
+	// file carries, if any, with -license-report.
+	carries, version string
+	// skipped, if set, names the RejectContent rule the file matched. The
+	// file was not checked, and the violation has no bucket.
+	skipped string
+	// enforceAfter is the EnforceAfter date of the license or rule the
+	// violation breaks.
+	enforceAfter time.Time
+	// resultKey, if not "", is the key of the result in the -cache.
+	resultKey string
+}
@@ -0,0 +1,23 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseBuckets(t *testing.T) {
+	got, err := parseBuckets("missing, forbidden")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[bucket]bool{bucketMissing: true, bucketForbidden: true}; !reflect.DeepEqual(got, want) {
+		t.Errorf("parseBuckets() = %v, want %v", got, want)
+	}
+	if _, err := parseBuckets("missing,bogus"); err == nil {
+		t.Error("parseBuckets(bogus) succeeded, want error")
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+)
+
+// compileVolatile compiles the VolatilePatterns regexps.
+func (c *Config) compileVolatile() error {
+	c.volatile = make([]*regexp.Regexp, 0, len(c.VolatilePatterns))
+	for i, s := range c.VolatilePatterns {
+		re, err := regexp.Compile(s)
+		if err != nil {
+			return fmt.Errorf("VolatilePatterns[%d]: invalid regexp %q: %v", i, s, err)
+		}
+		c.volatile = append(c.volatile, re)
+	}
+	return nil
+}
+
+// maskVolatile returns contents with the text matching VolatilePatterns
+// removed from their first contentHeadSize bytes, and the indexes of the
+// patterns which matched. Lines left holding nothing but comment markers
+// are removed too, so that licenses match as if the volatile text had never
+// been there.
+func (c *Config) maskVolatile(contents []byte) ([]byte, []int) {
+	if len(c.volatile) == 0 {
+		return contents, nil
+	}
+	head, rest := contents, []byte(nil)
+	if len(contents) > contentHeadSize {
+		end := contentHeadSize
+		if i := bytes.IndexByte(contents[end:], '\n'); i >= 0 {
+			end += i + 1
+		} else {
+			end = len(contents)
+		}
+		head, rest = contents[:end], contents[end:]
+	}
+	var masked bytes.Buffer
+	var applied []int
+	used := make([]bool, len(c.volatile))
+	for _, line := range bytes.SplitAfter(head, []byte("\n")) {
+		hit := false
+		for i, re := range c.volatile {
+			if !re.Match(line) {
+				continue
+			}
+			line, hit = re.ReplaceAll(line, nil), true
+			if !used[i] {
+				used[i] = true
+				applied = append(applied, i)
+			}
+		}
+		if hit && len(bytes.Trim(line, " \t\r\n/#*!<>-")) == 0 {
+			continue
+		}
+		masked.Write(line)
+	}
+	if applied == nil {
+		return contents, nil
+	}
+	masked.Write(rest)
+	return masked.Bytes(), applied
+}
@@ -0,0 +1,61 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestVolatilePatterns(t *testing.T) {
+	c := &Config{
+		Licenses:         []License{{Lines: []string{"^// Copyright 2026 X", "// BSD"}}},
+		VolatilePatterns: []string{`// Generated by gen v[0-9.]+\n`, `, built [0-9-]+`, `Build ID: [0-9a-f]+`},
+	}
+	if err := c.CompileRegexps(); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		name    string
+		in      string
+		masked  string
+		applied []int
+		want    bucket
+	}{
+		{
+			name:   "stable",
+			in:     "// Copyright 2026 X\n// BSD\npackage a\n",
+			masked: "// Copyright 2026 X\n// BSD\npackage a\n",
+		},
+		{
+			name:    "whole line",
+			in:      "// Copyright 2026 X\n// Generated by gen v1.2.3\n// BSD\npackage a\n",
+			masked:  "// Copyright 2026 X\n// BSD\npackage a\n",
+			applied: []int{0},
+		},
+		{
+			name:    "within a line",
+			in:      "// Copyright 2026 X, built 2026-10-14\n// BSD\n// Build ID: 0abc\npackage a\n",
+			masked:  "// Copyright 2026 X\n// BSD\npackage a\n",
+			applied: []int{1, 2},
+		},
+		{
+			name:   "license text differs",
+			in:     "// Copyright 2026 X\n// MIT\npackage a\n",
+			masked: "// Copyright 2026 X\n// MIT\npackage a\n",
+			want:   bucketMissing,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			masked, applied := c.maskVolatile([]byte(tt.in))
+			if string(masked) != tt.masked || !reflect.DeepEqual(applied, tt.applied) {
+				t.Errorf("maskVolatile(%q) = %q, %v, want %q, %v", tt.in, masked, applied, tt.masked, tt.applied)
+			}
+			if got := c.checkContents("a.go", []byte(tt.in)); got != tt.want {
+				t.Errorf("checkContents(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
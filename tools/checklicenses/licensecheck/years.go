@@ -0,0 +1,90 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// copyrightYears matches the years on a conventional copyright line, like
+// "Copyright 2017-2018 the u-root Authors".
+var copyrightYears = regexp.MustCompile(`Copyright (?:\([cC]\) |© )?(` + anyYears + `)`)
+
+// yearDigits matches each year in a list or range of years.
+var yearDigits = regexp.MustCompile(`[0-9]{4}`)
+
+// checkYearRange returns an error if MinYear is after MaxYear.
+func (c *Config) checkYearRange() error {
+	if c.MinYear != 0 && c.MaxYear != 0 && c.MinYear > c.MaxYear {
+		return fmt.Errorf("MinYear %d is after MaxYear %d", c.MinYear, c.MaxYear)
+	}
+	return nil
+}
+
+// years returns the copyright years claimed in the license header of file.
+// They are the "year" subexpression of the matching license, or else the
+// years on the first Copyright line. Ranges yield their two ends.
+func (c *Config) years(file string, contents []byte) []int {
+	var claimed []byte
+	texts := c.headers(file, contents)
+	if re, text := firstMatch(c.licensesRegexps, texts); re != nil {
+		if i := re.SubexpIndex("year"); i >= 0 {
+			if m := re.FindSubmatch(text); m != nil {
+				claimed = m[i]
+			}
+		}
+	}
+	if claimed == nil {
+		if m := copyrightYears.FindSubmatch(c.headerText(file, contents)); m != nil {
+			claimed = m[1]
+		}
+	}
+	var years []int
+	for _, y := range yearDigits.FindAll(claimed, -1) {
+		n, _ := strconv.Atoi(string(y))
+		years = append(years, n)
+	}
+	return years
+}
+
+// staleYears reports whether the copyright years claimed in the header of
+// file fail to span its history, from firstYear, the year it was first
+// committed, to lastYear, the year it was last changed. A firstYear of 0 is
+// unknown. Files claiming no years are not stale.
+func (c *Config) staleYears(file string, contents []byte, firstYear, lastYear int) bool {
+	years := c.years(file, contents)
+	if len(years) == 0 {
+		return false
+	}
+	first, last := years[0], years[0]
+	for _, y := range years[1:] {
+		if y < first {
+			first = y
+		}
+		if y > last {
+			last = y
+		}
+	}
+	if firstYear != 0 && firstYear < first {
+		return true
+	}
+	return lastYear < first || lastYear > last
+}
+
+// yearOutOfRange reports whether the header of file claims a copyright year
+// before MinYear or after MaxYear.
+func (c *Config) yearOutOfRange(file string, contents []byte) bool {
+	if c.MinYear == 0 && c.MaxYear == 0 {
+		return false
+	}
+	for _, y := range c.years(file, contents) {
+		if (c.MinYear != 0 && y < c.MinYear) || (c.MaxYear != 0 && y > c.MaxYear) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+// Copyright 2026 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licensecheck
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestYearRange(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		licenses []License
+		in       string
+		want     bucket
+	}{
+		{
+			name: "in range",
+			in:   "// Copyright 2015-2018 the u-root Authors\n// BSD\npackage a\n",
+		},
+		{
+			name: "before",
+			in:   "// Copyright 2008 the u-root Authors\n// BSD\npackage a\n",
+			want: bucketYearRange,
+		},
+		{
+			name: "range ends after",
+			in:   "// Copyright 2019, 2031 the u-root Authors\n// BSD\npackage a\n",
+			want: bucketYearRange,
+		},
+		{
+			name:     "year subexpression",
+			licenses: []License{{Lines: []string{`^// \(c\) the u-root Authors, (?P<year>[0-9]{4})`, "// BSD"}}},
+			in:       "// (c) the u-root Authors, 2008\n// BSD\npackage a\n",
+			want:     bucketYearRange,
+		},
+		{
+			name: "no years",
+			in:   "// the u-root Authors\n// BSD\npackage a\n",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Config{Licenses: tt.licenses, MinYear: 2015, MaxYear: 2030}
+			if c.Licenses == nil {
+				c.Licenses = []License{{Lines: []string{"// BSD"}}}
+			}
+			if err := c.CompileRegexps(); err != nil {
+				t.Fatal(err)
+			}
+			if got := c.checkContents("a.go", []byte(tt.in)); got != tt.want {
+				t.Errorf("checkContents(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+	c := &Config{MinYear: 2030, MaxYear: 2015}
+	if err := c.CompileRegexps(); err == nil {
+		t.Error("CompileRegexps() with MinYear after MaxYear succeeded, want error")
+	}
+}
+
+func TestStaleYears(t *testing.T) {
+	c := &Config{Licenses: []License{{Lines: []string{"// BSD"}}}}
+	if err := c.CompileRegexps(); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		in                  string
+		firstYear, lastYear int
+		want                bucket
+	}{
+		{in: "// Copyright 2017-2018 the u-root Authors\n// BSD\n", lastYear: 2018},
+		{in: "// Copyright 2017-2018 the u-root Authors\n// BSD\n", lastYear: 2026, want: bucketStaleYear},
+		{in: "// Copyright 2020 the u-root Authors\n// BSD\n", lastYear: 2019, want: bucketStaleYear},
+		{in: "// Copyright 2020 the u-root Authors\n// BSD\n"},
+		{in: "// the u-root Authors\n// BSD\n", lastYear: 2026},
+		{in: "// Copyright 2017-2018 the u-root Authors\n// BSD\n", firstYear: 2017, lastYear: 2018},
+		{in: "// Copyright 2017-2018 the u-root Authors\n// BSD\n", firstYear: 2018, lastYear: 2018},
+		{in: "// Copyright 2018 the u-root Authors\n// BSD\n", firstYear: 2017, lastYear: 2018, want: bucketStaleYear},
+		{in: "// Copyright 2018-2019 the u-root Authors\n// BSD\n", firstYear: 2017, lastYear: 2026, want: bucketStaleYear},
+	} {
+		cd := candidate{file: "a.go", config: c, firstYear: tt.firstYear, lastYear: tt.lastYear}
+		if got := cd.classify("a.go", []byte(tt.in)).bucket; got != tt.want {
+			t.Errorf("classify(%q) changed from %d to %d = %q, want %q", tt.in, tt.firstYear, tt.lastYear, got, tt.want)
+		}
+	}
+}
+
+func TestGitCommitYears(t *testing.T) {
+	dir := t.TempDir()
+	script := "#!/bin/sh\nprintf '\\0002026\\na.go\\nb.go\\n\\n\\0002019\\na.go\\nc.go\\n'\n"
+	if err := os.WriteFile(filepath.Join(dir, "git"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	first, last, err := gitCommitYears(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]int{"a.go": 2019, "b.go": 2026, "c.go": 2019}; !reflect.DeepEqual(first, want) {
+		t.Errorf("gitCommitYears() first = %v, want %v", first, want)
+	}
+	if want := map[string]int{"a.go": 2026, "b.go": 2026, "c.go": 2019}; !reflect.DeepEqual(last, want) {
+		t.Errorf("gitCommitYears() last = %v, want %v", last, want)
+	}
+}
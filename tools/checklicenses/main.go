@@ -0,0 +1,35 @@
+// Copyright 2017-2018 the u-root Authors. All rights reserved
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Run with `go run ./tools/checklicenses`. It outputs a list of files which do
+// not conform. With -fix, files without a license get the header from
+// .license-header.txt at the top of the repository (or -template) inserted.
+// -fix-only limits the fixes to some buckets, and -dry-run lists the files
+// -fix would change instead of changing them.
+//
+// The exit code tells callers what happened:
+//
+//	0   all checked files carry an acceptable license
+//	1   license violations were found, in the -fail-on buckets if given, of
+//	    rules already enforced, and -no-fail was not set, nor were they the
+//	    same as last run with -quiet-unless-changed; or, with
+//	    -require-all-licenses-used, some of the Licenses went unused; or, with
+//	    -deps, some module carries a license not in DepLicenses
+//	2   the configuration or command line is invalid, or no files were selected;
+//	    an invalid configuration exits 0 instead with -exit-zero-on-config-error,
+//	    which is only meant for rolling out a new configuration
+//	3   files could not be listed, read, decompressed, or checked within -file-timeout
+//	4   the run did not finish within -timeout
+//	130 the scan was interrupted
+package main
+
+import (
+	"os"
+
+	"github.com/u-root/u-root/tools/checklicenses/licensecheck"
+)
+
+func main() {
+	os.Exit(licensecheck.Main(os.Args[1:], os.Stdout, os.Stderr))
+}
@@ -14,6 +14,7 @@ import (
 	"runtime"
 	"sort"
 	"strings"
+	"text/template"
 	"time"
 
 	gbbgolang "github.com/u-root/gobusybox/src/pkg/golang"
@@ -45,10 +46,15 @@ var (
 	useExistingInit                         *bool
 	noCommands                              *bool
 	extraFiles                              multiFlag
+	manifests                               multiFlag
 	statsOutputPath                         *string
 	statsLabel                              *string
 	shellbang                               *bool
 	tags                                    *string
+	compress                                *string
+	arch                                    *string
+	buildCache                              *string
+	compressLevel                           *int
 	// For the new gobusybox support
 	usegobusybox *bool
 	genDir       *string
@@ -79,14 +85,21 @@ func init() {
 	noCommands = flag.Bool("nocmd", false, "Build no Go commands; initramfs only")
 
 	flag.Var(&extraFiles, "files", "Additional files, directories, and binaries (with their ldd dependencies) to add to archive. Can be speficified multiple times.")
+	flag.Var(&manifests, "manifest", "YAML or JSON manifest of files, directories, symlinks and device nodes to add to archive, with their ownership and permissions. Can be specified multiple times.")
 
 	shellbang = flag.Bool("shellbang", false, "Use #! instead of symlinks for busybox")
+	buildCache = flag.String("build-cache", "", "Directory to cache busybox binaries in, e.g. ~/.cache/u-root, so that rebuilding unchanged commands takes no compilation. Binaries are not cached by default.")
 
 	statsOutputPath = flag.String("stats-output-path", "", "Write build stats to this file (JSON)")
 	statsLabel = flag.String("stats-label", "", "Use this statsLabel when writing stats")
 
 	tags = flag.String("tags", "", "Comma separated list of build tags")
 
+	arch = flag.String("arch", "", "Comma separated list of GOARCHs to build an initramfs for in one run, e.g. amd64,arm64,riscv64 (default GOARCH). {{.GOOS}} and {{.GOARCH}} in -o are replaced by those of each initramfs.")
+
+	compress = flag.String("compress", "", "Compress the cpio archive with gzip, lz4, xz or zstd.")
+	compressLevel = flag.Int("compress-level", 0, "Compression level for -compress; 0 uses the default level of the compression.")
+
 	// Flags for the gobusybox, which we hope to move to, since it works with modules.
 	genDir = flag.String("gen-dir", "", "Directory to generate source in")
 
@@ -128,9 +141,8 @@ func writeBuildStats(stats buildStats, path string) error {
 	return nil
 }
 
-func generateLabel() string {
+func generateLabel(env golang.Environ) string {
 	var baseCmds []string
-	env := golang.Default()
 	if len(flag.Args()) > 0 {
 		// Use the last component of the name to keep the label short
 		for _, e := range flag.Args() {
@@ -155,28 +167,36 @@ func main() {
 	start := time.Now()
 
 	// Main is in a separate functions so defers run on return.
-	if err := Main(l, gbbOpts); err != nil {
+	outputs, err := Main(l, gbbOpts)
+	if err != nil {
 		l.Fatalf("Build error: %v", err)
 	}
 
-	elapsed := time.Now().Sub(start)
-
-	stats := buildStats{
-		Label:    *statsLabel,
-		Time:     start.Unix(),
-		Duration: float64(elapsed.Milliseconds()) / 1000,
-	}
-	if stats.Label == "" {
-		stats.Label = generateLabel()
-	}
-	if stat, err := os.Stat(*outputPath); err == nil && stat.ModTime().After(start) {
-		l.Printf("Successfully built %q (size %d).", *outputPath, stat.Size())
-		stats.OutputSize = stat.Size()
-		if *statsOutputPath != "" {
-			if err := writeBuildStats(stats, *statsOutputPath); err == nil {
-				l.Printf("Wrote stats to %q (label %q)", *statsOutputPath, stats.Label)
-			} else {
-				l.Printf("Failed to write stats to %s: %v", *statsOutputPath, err)
+	for _, o := range outputs {
+		// A single initramfs counts the setup before building it.
+		begin, duration := o.start, o.duration
+		if len(outputs) == 1 {
+			begin, duration = start, time.Since(start)
+		}
+		stats := buildStats{
+			Label:    *statsLabel,
+			Time:     begin.Unix(),
+			Duration: float64(duration.Milliseconds()) / 1000,
+		}
+		if stats.Label == "" {
+			stats.Label = generateLabel(o.env)
+		} else if len(outputs) > 1 {
+			stats.Label += "-" + o.env.GOARCH
+		}
+		if stat, err := os.Stat(o.path); err == nil && stat.ModTime().After(o.start) {
+			l.Printf("Successfully built %q (size %d).", o.path, stat.Size())
+			stats.OutputSize = stat.Size()
+			if *statsOutputPath != "" {
+				if err := writeBuildStats(stats, *statsOutputPath); err == nil {
+					l.Printf("Wrote stats to %q (label %q)", *statsOutputPath, stats.Label)
+				} else {
+					l.Printf("Failed to write stats to %s: %v", *statsOutputPath, err)
+				}
 			}
 		}
 	}
@@ -196,8 +216,8 @@ func isRecommendedVersion(v string) bool {
 }
 
 // Main is a separate function so defers are run on return, which they wouldn't
-// on exit.
-func Main(l ulog.Logger, buildOpts *gbbgolang.BuildOpts) error {
+// on exit. It returns the initramfs files it built, one for each -arch.
+func Main(l ulog.Logger, buildOpts *gbbgolang.BuildOpts) ([]output, error) {
 	env := golang.Default()
 	env.BuildTags = strings.Split(*tags, ",")
 	if env.CgoEnabled {
@@ -224,31 +244,56 @@ func Main(l ulog.Logger, buildOpts *gbbgolang.BuildOpts) error {
 
 	archiver, err := initramfs.GetArchiver(*format)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	var ext string
+	if *compress != "" {
+		ca, ok := archiver.(initramfs.CPIOArchiver)
+		if !ok {
+			return nil, fmt.Errorf("-compress only applies to the cpio format, not %q", *format)
+		}
+		c, err := initramfs.GetCompressor(*compress, *compressLevel)
+		if err != nil {
+			return nil, err
+		}
+		ca.Compression, ca.CompressionLevel = *compress, *compressLevel
+		archiver, ext = ca, c.Ext
 	}
 
-	// Open the target initramfs file.
+	// Name the target initramfs files.
+	archs := []string{env.GOARCH}
+	if *arch != "" {
+		archs = strings.Split(*arch, ",")
+	}
 	if *outputPath == "" {
-		if len(env.GOOS) == 0 && len(env.GOARCH) == 0 {
-			return fmt.Errorf("passed no path, GOOS, and GOARCH to CPIOArchiver.OpenWriter")
+		if len(env.GOOS) == 0 && len(env.GOARCH) == 0 && *arch == "" {
+			return nil, fmt.Errorf("passed no path, GOOS, and GOARCH to CPIOArchiver.OpenWriter")
 		}
-		*outputPath = fmt.Sprintf("/tmp/initramfs.%s_%s.cpio", env.GOOS, env.GOARCH)
+		*outputPath = "/tmp/initramfs.{{.GOOS}}_{{.GOARCH}}.cpio" + ext
 	}
-	w, err := archiver.OpenWriter(l, *outputPath)
-	if err != nil {
-		return err
+	var outputs []output
+	for _, a := range archs {
+		archEnv := env
+		archEnv.GOARCH = a
+		path, err := expandOutputPath(*outputPath, archEnv)
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range outputs {
+			if o.path == path {
+				return nil, fmt.Errorf("-o %q names the same file %q for GOARCH %s and %s; use {{.GOARCH}} in it", *outputPath, path, o.env.GOARCH, a)
+			}
+		}
+		outputs = append(outputs, output{env: archEnv, path: path})
 	}
 
-	var baseFile initramfs.Reader
+	var bf *os.File
 	if *base != "" {
-		bf, err := os.Open(*base)
+		bf, err = os.Open(*base)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		defer bf.Close()
-		baseFile = archiver.Reader(bf)
-	} else {
-		baseFile = uroot.DefaultRamfs().Reader()
 	}
 
 	tempDir := *tmpDir
@@ -256,33 +301,33 @@ func Main(l ulog.Logger, buildOpts *gbbgolang.BuildOpts) error {
 		var err error
 		tempDir, err = os.MkdirTemp("", "u-root")
 		if err != nil {
-			return err
+			return nil, err
 		}
 		defer os.RemoveAll(tempDir)
 	} else if _, err := os.Stat(tempDir); os.IsNotExist(err) {
 		if err := os.MkdirAll(tempDir, 0o755); err != nil {
-			return fmt.Errorf("temporary directory %q did not exist; tried to mkdir but failed: %v", tempDir, err)
+			return nil, fmt.Errorf("temporary directory %q did not exist; tried to mkdir but failed: %v", tempDir, err)
 		}
 	}
 
 	var (
-		c           []uroot.Commands
+		b           builder.Builder
+		pkgs        []string
 		initCommand = *initCmd
 	)
 	if !*noCommands {
-		var b builder.Builder
 		switch *build {
 		case "bb":
-			b = builder.BBBuilder{ShellBang: *shellbang}
+			b = builder.BBBuilder{ShellBang: *shellbang, Cache: builder.Cache{Dir: *buildCache}}
 		case "gbb":
 			l.Printf("NOTE: building with the new gobusybox; to get old behavior, use -build=bb")
-			b = builder.GBBBuilder{ShellBang: *shellbang}
+			b = builder.GBBBuilder{ShellBang: *shellbang, Cache: builder.Cache{Dir: *buildCache}}
 		case "binary":
 			b = builder.BinaryBuilder{}
 		case "source":
-			return fmt.Errorf("source mode has been deprecated")
+			return nil, fmt.Errorf("source mode has been deprecated")
 		default:
-			return fmt.Errorf("could not find builder %q", *build)
+			return nil, fmt.Errorf("could not find builder %q", *build)
 		}
 
 		// Resolve globs into package imports.
@@ -290,7 +335,6 @@ func Main(l ulog.Logger, buildOpts *gbbgolang.BuildOpts) error {
 		// Currently allowed formats:
 		//   Go package imports; e.g. github.com/u-root/u-root/cmds/ls (must be in $GOPATH)
 		//   Paths to Go package directories; e.g. $GOPATH/src/github.com/u-root/u-root/cmds/*
-		var pkgs []string
 		for _, a := range flag.Args() {
 			p, ok := templates[a]
 			if !ok {
@@ -302,33 +346,89 @@ func Main(l ulog.Logger, buildOpts *gbbgolang.BuildOpts) error {
 		if len(pkgs) == 0 {
 			pkgs = []string{"github.com/u-root/u-root/cmds/core/*"}
 		}
-
-		// The command-line tool only allows specifying one build mode
-		// right now.
-		c = append(c, uroot.Commands{
-			Builder:  b,
-			Packages: pkgs,
-		})
 	}
 
-	opts := uroot.Opts{
-		Env:             env,
-		Commands:        c,
-		TempDir:         tempDir,
-		ExtraFiles:      extraFiles,
-		OutputFile:      w,
-		BaseArchive:     baseFile,
-		UseExistingInit: *useExistingInit,
-		InitCmd:         initCommand,
-		DefaultShell:    *defaultShell,
-		BuildOpts:       buildOpts,
+	// Each initramfs resolves the packages with its own GOARCH, which
+	// selects the files a glob of package directories matches.
+	for i := range outputs {
+		o := &outputs[i]
+		o.start = time.Now()
+		if len(outputs) > 1 {
+			l.Printf("Building %s/%s initramfs %q", o.env.GOOS, o.env.GOARCH, o.path)
+		}
+		w, err := archiver.OpenWriter(l, o.path)
+		if err != nil {
+			return nil, err
+		}
+
+		var baseFile initramfs.Reader
+		if bf != nil {
+			baseFile = archiver.Reader(bf)
+		} else {
+			baseFile = uroot.DefaultRamfs().Reader()
+		}
+
+		var c []uroot.Commands
+		if !*noCommands {
+			// The command-line tool only allows specifying one build mode
+			// right now.
+			c = append(c, uroot.Commands{
+				Builder:  b,
+				Packages: append([]string{}, pkgs...),
+			})
+		}
+
+		opts := uroot.Opts{
+			Env:             o.env,
+			Commands:        c,
+			TempDir:         tempDir,
+			ExtraFiles:      extraFiles,
+			Manifests:       manifests,
+			OutputFile:      w,
+			BaseArchive:     baseFile,
+			UseExistingInit: *useExistingInit,
+			InitCmd:         initCommand,
+			DefaultShell:    *defaultShell,
+			BuildOpts:       buildOpts,
+		}
+		uinitArgs := shlex.Argv(*uinitCmd)
+		if len(uinitArgs) > 0 {
+			opts.UinitCmd = uinitArgs[0]
+		}
+		if len(uinitArgs) > 1 {
+			opts.UinitArgs = uinitArgs[1:]
+		}
+		if err := uroot.CreateInitramfs(l, opts); err != nil {
+			if len(outputs) > 1 {
+				return nil, fmt.Errorf("GOARCH %s: %v", o.env.GOARCH, err)
+			}
+			return nil, err
+		}
+		o.duration = time.Since(o.start)
 	}
-	uinitArgs := shlex.Argv(*uinitCmd)
-	if len(uinitArgs) > 0 {
-		opts.UinitCmd = uinitArgs[0]
+	return outputs, nil
+}
+
+// output is an initramfs built by Main.
+type output struct {
+	env  golang.Environ
+	path string
+
+	// start is when building it started, and duration how long it took.
+	start    time.Time
+	duration time.Duration
+}
+
+// expandOutputPath returns the -o path of the initramfs built in env, in
+// which {{.GOOS}} and {{.GOARCH}} are replaced by those of env.
+func expandOutputPath(path string, env golang.Environ) (string, error) {
+	t, err := template.New("-o").Option("missingkey=error").Parse(path)
+	if err != nil {
+		return "", fmt.Errorf("invalid -o %q: %v", path, err)
 	}
-	if len(uinitArgs) > 1 {
-		opts.UinitArgs = uinitArgs[1:]
+	var b strings.Builder
+	if err := t.Execute(&b, struct{ GOOS, GOARCH string }{env.GOOS, env.GOARCH}); err != nil {
+		return "", fmt.Errorf("invalid -o %q: %v", path, err)
 	}
-	return uroot.CreateInitramfs(l, opts)
+	return b.String(), nil
 }
@@ -345,6 +345,27 @@ func buildIt(t *testing.T, args, env []string, want error) (*os.File, []byte) {
 	return f, nil
 }
 
+func TestExpandOutputPath(t *testing.T) {
+	env := golang.Default()
+	env.GOOS, env.GOARCH = "linux", "riscv64"
+	for _, tt := range []struct {
+		path string
+		want string
+		err  bool
+	}{
+		{path: "/tmp/initramfs.cpio", want: "/tmp/initramfs.cpio"},
+		{path: "/tmp/initramfs.{{.GOOS}}_{{.GOARCH}}.cpio", want: "/tmp/initramfs.linux_riscv64.cpio"},
+		{path: "out/{{.GOARCH}}/initramfs.cpio.zst", want: "out/riscv64/initramfs.cpio.zst"},
+		{path: "/tmp/initramfs.{{.GOARM}}.cpio", err: true},
+		{path: "/tmp/initramfs.{{.GOARCH.cpio", err: true},
+	} {
+		got, err := expandOutputPath(tt.path, env)
+		if (err != nil) != tt.err || got != tt.want {
+			t.Errorf("expandOutputPath(%q) = %q, %v, want %q, error %t", tt.path, got, err, tt.want, tt.err)
+		}
+	}
+}
+
 func TestMain(m *testing.M) {
 	testutil.Run(m, main)
 }